@@ -0,0 +1,159 @@
+package clinvar
+
+// traitMappingKey identifies a TraitMapping: the submitted trait of one clinical assertion, matched
+// either by name (MappingRef is the ElementValue type) or by cross-reference (MappingRef is the XRef DB)
+type traitMappingKey struct {
+	clinicalAssertionID string
+	traitType           string
+	mappingType         string
+	mappingValue        string
+	mappingRef          string
+}
+
+// medGenConcept is the MedGen concept a TraitMapping resolves a submitted trait to
+type medGenConcept struct {
+	cui  string
+	name string
+}
+
+func (variant *VariationArchive) traitMappings() map[traitMappingKey]medGenConcept {
+	mappings := make(map[traitMappingKey]medGenConcept)
+	for _, mapping := range variant.record().TraitMappingList.TraitMapping {
+		mappings[traitMappingKey{
+			clinicalAssertionID: mapping.ClinicalAssertionID,
+			traitType:           mapping.TraitType,
+			mappingType:         mapping.MappingType,
+			mappingValue:        mapping.MappingValue,
+			mappingRef:          mapping.MappingRef}] = medGenConcept{cui: mapping.MedGen.CUI, name: mapping.MedGen.Name}
+	}
+	return mappings
+}
+
+// extractClinicalAssertions surfaces every submitter-level record (SCV) of the variant
+func (variant *VariationArchive) extractClinicalAssertions() []ClinicalAssertions {
+	mappings := variant.traitMappings()
+	variantAllAssertions := []ClinicalAssertions{}
+	for _, assertion := range variant.record().ClinicalAssertionList.ClinicalAssertion {
+		na := ClinicalAssertions{
+			ID:                   assertion.ID,
+			Accession:            assertion.ClinVarAccession.Accession,
+			Version:              assertion.ClinVarAccession.Version,
+			SubmitterName:        assertion.ClinVarAccession.SubmitterName,
+			OrgID:                assertion.ClinVarAccession.OrgID,
+			OrganizationCategory: assertion.ClinVarAccession.OrganizationCategory,
+			OrgAbbreviation:      assertion.ClinVarAccession.OrgAbbreviation,
+			SubmissionDate:       assertion.SubmissionDate,
+			DateLastUpdated:      assertion.DateLastUpdated,
+			RecordStatus:         assertion.RecordStatus,
+			ReviewStatus:         assertion.ReviewStatus,
+			Interpretation:       assertion.Interpretation.Description,
+			DateLastEvaluated:    assertion.Interpretation.DateLastEvaluated,
+			Comments:             []string{},
+			Citations:            []Citations{},
+			Conditions:           []AssertionConditions{},
+			Observations:         []Observation{},
+		}
+
+		//Schema 2.x submissions describe their classification in Classification instead of Interpretation
+		if na.Interpretation == "" {
+			na.Interpretation = assertion.Classification.GermlineClassification
+		}
+		if na.DateLastEvaluated == "" {
+			na.DateLastEvaluated = assertion.Classification.DateLastEvaluated
+		}
+		if na.ReviewStatus == "" {
+			na.ReviewStatus = assertion.Classification.ReviewStatus
+		}
+		na.Oncogenicity = assertion.Classification.OncogenicityClassification
+		if impact := assertion.Classification.SomaticClinicalImpact; impact != nil {
+			na.SomaticClinicalImpact = &SomaticClinicalImpactTier{
+				Tier:                 impact.Text,
+				AssertionType:        impact.ClinicalImpactAssertionType,
+				ClinicalSignificance: impact.ClinicalImpactClinicalSignificance}
+		}
+
+		for _, attributeSet := range assertion.AttributeSet {
+			switch attributeSet.Attribute.Type {
+			case "AssertionMethod":
+				na.AssertionMethod = attributeSet.Attribute.Text
+			case "ModeOfInheritance":
+				na.ModeOfInheritance = attributeSet.Attribute.Text
+			}
+		}
+
+		for _, comment := range assertion.Interpretation.Comment {
+			na.Comments = append(na.Comments, comment.Text)
+		}
+		for _, comment := range assertion.Classification.Comment {
+			na.Comments = append(na.Comments, comment.Text)
+		}
+		for _, comment := range assertion.Comment {
+			na.Comments = append(na.Comments, comment.Text)
+		}
+
+		for _, citation := range assertion.Interpretation.Citation {
+			na.Citations = append(na.Citations, Citations{
+				CitationSource: citation.ID.Source,
+				CitationID:     citation.ID.Text,
+				URL:            citation.URL})
+		}
+		for _, citation := range assertion.Classification.Citation {
+			na.Citations = append(na.Citations, Citations{
+				CitationSource: citation.ID.Source,
+				CitationID:     citation.ID.Text,
+				URL:            citation.URL})
+		}
+
+		for _, observed := range assertion.ObservedInList.ObservedIn {
+			no := Observation{
+				Origin:         observed.Sample.Origin,
+				Species:        observed.Sample.Species.Text,
+				TaxonomyID:     observed.Sample.Species.TaxonomyId,
+				AffectedStatus: observed.Sample.AffectedStatus,
+				NumberTested:   parseCount(observed.Sample.NumberTested),
+				MethodType:     observed.Method.MethodType,
+				TypePlatform:   observed.Method.TypePlatform,
+				ObservedData:   []ObservedDataAttribute{},
+			}
+			for _, data := range observed.ObservedData {
+				if data.Attribute.Type == "" {
+					continue
+				}
+				no.ObservedData = append(no.ObservedData, ObservedDataAttribute{
+					Type:         data.Attribute.Type,
+					Value:        data.Attribute.Text,
+					IntegerValue: parseCount(data.Attribute.IntegerValue)})
+			}
+			na.Observations = append(na.Observations, no)
+		}
+
+		//Resolve each submitted trait to MedGen, trying its names before its cross-references
+		for _, trait := range assertion.TraitSet.Trait {
+			nc := AssertionConditions{TraitType: trait.Type}
+			for _, name := range trait.Name {
+				if nc.SubmittedName == "" {
+					nc.SubmittedName = name.ElementValue.Text
+				}
+				key := traitMappingKey{assertion.ID, trait.Type, "Name", name.ElementValue.Text, name.ElementValue.Type}
+				if concept, ok := mappings[key]; ok && nc.MedGenCUI == "" {
+					nc.SubmittedName = name.ElementValue.Text
+					nc.MedGenCUI, nc.MedGenName = concept.cui, concept.name
+				}
+			}
+			for _, xref := range trait.XRef {
+				if nc.SubmittedXRef == "" {
+					nc.SubmittedXRef = xref.DB + ":" + xref.ID
+				}
+				key := traitMappingKey{assertion.ID, trait.Type, "XRef", xref.ID, xref.DB}
+				if concept, ok := mappings[key]; ok && nc.MedGenCUI == "" {
+					nc.SubmittedXRef = xref.DB + ":" + xref.ID
+					nc.MedGenCUI, nc.MedGenName = concept.cui, concept.name
+				}
+			}
+			na.Conditions = append(na.Conditions, nc)
+		}
+
+		variantAllAssertions = append(variantAllAssertions, na)
+	}
+	return variantAllAssertions
+}
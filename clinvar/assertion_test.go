@@ -0,0 +1,35 @@
+package clinvar
+
+import "testing"
+
+const traitMappingExample = "testdata/ClinVarVariationRelease_traitMappingExample.xml"
+
+func TestTraitMappings(t *testing.T) {
+	_, variants := readExample(t, traitMappingExample)
+	if len(variants) != 1 {
+		t.Fatalf("got %d variants, want 1", len(variants))
+	}
+	assertions := variants[0].ClinicalAssertions
+	want := []AssertionConditions{
+		//Mapped by the name of the submitted trait
+		{TraitType: "Disease", SubmittedName: "Li-Fraumeni syndrome", MedGenCUI: "C0085390", MedGenName: "Li-Fraumeni syndrome"},
+		//Mapped by the second of its XRefs, which is the one reported
+		{TraitType: "Disease", SubmittedName: "LFS", SubmittedXRef: "Orphanet:ORPHA524", MedGenCUI: "C0085390", MedGenName: "Li-Fraumeni syndrome"},
+		//The mapping's MappingRef is another ElementValue type than the submitted name's
+		{TraitType: "Disease", SubmittedName: "Hereditary cancer-predisposing syndrome"},
+		//The mappings for this name and XRef belong to other SCVs
+		{TraitType: "Disease", SubmittedName: "Li-Fraumeni syndrome", SubmittedXRef: "Orphanet:ORPHA524"},
+	}
+	if len(assertions) != len(want) {
+		t.Fatalf("got %d SCVs, want %d", len(assertions), len(want))
+	}
+	for i, assertion := range assertions {
+		if len(assertion.Conditions) != 1 {
+			t.Errorf("%s: got %d conditions, want 1", assertion.Accession, len(assertion.Conditions))
+			continue
+		}
+		if got := assertion.Conditions[0]; got != want[i] {
+			t.Errorf("%s: condition = %+v, want %+v", assertion.Accession, got, want[i])
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package clinvar
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// AggregateClassification is the aggregate classification of a variant: the Interpretation of schema 1.x
+// records, or the GermlineClassification and OncogenicityClassification of schema 2.x records
+type AggregateClassification struct {
+	Text                string `xml:",chardata"`
+	DateLastEvaluated   string `xml:"DateLastEvaluated,attr"`
+	NumberOfSubmissions string `xml:"NumberOfSubmissions,attr"`
+	NumberOfSubmitters  string `xml:"NumberOfSubmitters,attr"`
+	Type                string `xml:"Type,attr"`
+	ReviewStatus        string `xml:"ReviewStatus"`
+	Description         string `xml:"Description"`
+	Citation            []struct {
+		Text string `xml:",chardata"`
+		Type string `xml:"Type,attr"`
+		ID   struct {
+			Text   string `xml:",chardata"`
+			Source string `xml:"Source,attr"`
+		} `xml:"ID"`
+		URL string `xml:"URL"`
+	} `xml:"Citation"`
+	ConditionList struct {
+		Text     string `xml:",chardata"`
+		TraitSet []struct {
+			Text  string `xml:",chardata"`
+			ID    string `xml:"ID,attr"`
+			Type  string `xml:"Type,attr"`
+			Trait Trait  `xml:"Trait"`
+		} `xml:"TraitSet"`
+	} `xml:"ConditionList"`
+	DescriptionHistory []struct {
+		Text        string `xml:",chardata"`
+		Dated       string `xml:"Dated,attr"`
+		Description string `xml:"Description"`
+	} `xml:"DescriptionHistory"`
+}
+
+// Classifications replaces Interpretations in schema 2.x records, splitting the aggregate classification
+// into germline, somatic clinical impact and oncogenicity
+type Classifications struct {
+	Text                       string                          `xml:",chardata"`
+	GermlineClassification     *AggregateClassification        `xml:"GermlineClassification"`
+	SomaticClinicalImpact      *AggregateSomaticClinicalImpact `xml:"SomaticClinicalImpact"`
+	OncogenicityClassification *AggregateClassification        `xml:"OncogenicityClassification"`
+}
+
+// AggregateSomaticClinicalImpact is the aggregate AMP/ASCO/CAP tier of a schema 2.x record,
+// with one Description per type of clinical impact
+type AggregateSomaticClinicalImpact struct {
+	Text              string `xml:",chardata"`
+	DateLastEvaluated string `xml:"DateLastEvaluated,attr"`
+	ReviewStatus      string `xml:"ReviewStatus"`
+	Description       []struct {
+		Text                               string `xml:",chardata"`
+		ClinicalImpactAssertionType        string `xml:"ClinicalImpactAssertionType,attr"`
+		ClinicalImpactClinicalSignificance string `xml:"ClinicalImpactClinicalSignificance,attr"`
+	} `xml:"Description"`
+}
+
+// SomaticClinicalImpact is the aggregate somatic clinical impact of a variant
+type SomaticClinicalImpact struct {
+	ReviewStatus      string
+	DateLastEvaluated string
+	Tiers             []SomaticClinicalImpactTier
+}
+
+// SomaticClinicalImpactTier is a tier (e.g. "Tier I - Strong") with the type and significance of the impact it was assigned for
+type SomaticClinicalImpactTier struct {
+	Tier                 string
+	AssertionType        string
+	ClinicalSignificance string
+}
+
+// Oncogenicity is the aggregate oncogenicity classification of a variant
+type Oncogenicity struct {
+	Description       string
+	ReviewStatus      string
+	DateLastEvaluated string
+}
+
+// schemaVersionPattern matches the version at the end of a noNamespaceSchemaLocation, such as
+// variation_archive_1.10.xsd or ClinVar_VCV_2.0.xsd
+var schemaVersionPattern = regexp.MustCompile(`_(\d+)\.(\d+)\.xsd$`)
+
+// maxSchemaMajorVersion is the newest schema layout the VariationArchive struct decodes
+const maxSchemaMajorVersion = 2
+
+// schemaVersion returns the schema version named by a noNamespaceSchemaLocation, or "" when it names none
+func schemaVersion(schemaLocation string) string {
+	match := schemaVersionPattern.FindStringSubmatch(schemaLocation)
+	if match == nil {
+		return ""
+	}
+	return match[1] + "." + match[2]
+}
+
+// checkSchemaVersion rejects releases whose schema is newer than the layouts the parser knows.
+// This is the only use of the detected version: the layout of each record is told apart by the elements
+// it has (see record), so releases that do not name a schema are decoded as either layout
+func checkSchemaVersion(releaseInfo ClinVarDataReleaseInfo) error {
+	match := schemaVersionPattern.FindStringSubmatch(releaseInfo.ClinVarSchemaVersion)
+	if match == nil {
+		return nil
+	}
+	if major, _ := strconv.Atoi(match[1]); major > maxSchemaMajorVersion {
+		return fmt.Errorf("unsupported ClinVar schema version %s", releaseInfo.SchemaVersion)
+	}
+	return nil
+}
+
+// record returns the variant's InterpretedRecord, or its ClassifiedRecord in schema 2.x files. The layout
+// is detected from the element present rather than from SchemaVersion, as the two cannot disagree within
+// a valid file and the element is also there when the schema location is missing or renamed
+func (variant *VariationArchive) record() *InterpretedRecord {
+	if variant.ClassifiedRecord != nil {
+		return variant.ClassifiedRecord
+	}
+	return &variant.InterpretedRecord
+}
+
+// germlineClassification returns the aggregate germline classification of either schema layout
+func (record *InterpretedRecord) germlineClassification() *AggregateClassification {
+	if record.Classifications.GermlineClassification != nil {
+		return record.Classifications.GermlineClassification
+	}
+	return &record.Interpretations.Interpretation
+}
+
+// extractSomaticClinicalImpact returns the aggregate somatic clinical impact, or nil when the record has none
+func (variant *VariationArchive) extractSomaticClinicalImpact() *SomaticClinicalImpact {
+	impact := variant.record().Classifications.SomaticClinicalImpact
+	if impact == nil {
+		return nil
+	}
+	somatic := &SomaticClinicalImpact{
+		ReviewStatus:      impact.ReviewStatus,
+		DateLastEvaluated: impact.DateLastEvaluated,
+		Tiers:             []SomaticClinicalImpactTier{}}
+	for _, description := range impact.Description {
+		somatic.Tiers = append(somatic.Tiers, SomaticClinicalImpactTier{
+			Tier:                 description.Text,
+			AssertionType:        description.ClinicalImpactAssertionType,
+			ClinicalSignificance: description.ClinicalImpactClinicalSignificance})
+	}
+	return somatic
+}
+
+// extractOncogenicity returns the aggregate oncogenicity classification, or nil when the record has none
+func (variant *VariationArchive) extractOncogenicity() *Oncogenicity {
+	oncogenicity := variant.record().Classifications.OncogenicityClassification
+	if oncogenicity == nil {
+		return nil
+	}
+	return &Oncogenicity{
+		Description:       oncogenicity.Description,
+		ReviewStatus:      oncogenicity.ReviewStatus,
+		DateLastEvaluated: oncogenicity.DateLastEvaluated}
+}
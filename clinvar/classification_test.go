@@ -0,0 +1,134 @@
+package clinvar
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const schema2Example = "testdata/ClinVarVariationRelease_2.0Example.xml"
+
+// readExample extracts every variant of a sample release with a Reader
+func readExample(t *testing.T, path string) (ClinVarDataReleaseInfo, []ClinVarVariationData) {
+	t.Helper()
+	file, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	variants := []ClinVarVariationData{}
+	for {
+		variant, err := reader.Next()
+		if err == io.EOF {
+			return reader.ReleaseInfo(), variants
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		variants = append(variants, Extract(variant, DefaultAssembly))
+	}
+}
+
+func TestSchema2Classifications(t *testing.T) {
+	releaseInfo, variants := readExample(t, schema2Example)
+	if releaseInfo.SchemaVersion != "2.0" {
+		t.Errorf("SchemaVersion = %q, want 2.0", releaseInfo.SchemaVersion)
+	}
+	if len(variants) != 3 {
+		t.Fatalf("got %d variants, want 3", len(variants))
+	}
+
+	tp53 := variants[0]
+	if tp53.Accesssion != "VCV000012375" || tp53.RecordType != "classified" {
+		t.Errorf("got %s %s, want VCV000012375 classified", tp53.Accesssion, tp53.RecordType)
+	}
+	if tp53.Interpretation != "Pathogenic" || tp53.DateLastEvaluated != "2020-01-01" {
+		t.Errorf("germline classification = %q on %q, want Pathogenic on 2020-01-01", tp53.Interpretation, tp53.DateLastEvaluated)
+	}
+	//2.x records have no record-level ReviewStatus; the germline classification's is used first
+	if tp53.ReviewStatus != "reviewed by expert panel" {
+		t.Errorf("ReviewStatus = %q, want the germline review status", tp53.ReviewStatus)
+	}
+	wantImpact := &SomaticClinicalImpact{
+		ReviewStatus:      "criteria provided, single submitter",
+		DateLastEvaluated: "2023-06-01",
+		Tiers:             []SomaticClinicalImpactTier{{Tier: "Tier I - Strong", AssertionType: "prognostic", ClinicalSignificance: "poor outcome"}}}
+	if !reflect.DeepEqual(tp53.SomaticClinicalImpact, wantImpact) {
+		t.Errorf("SomaticClinicalImpact = %+v, want %+v", tp53.SomaticClinicalImpact, wantImpact)
+	}
+	wantOncogenicity := &Oncogenicity{Description: "Oncogenic", ReviewStatus: "criteria provided, single submitter", DateLastEvaluated: "2023-09-01"}
+	if !reflect.DeepEqual(tp53.Oncogenicity, wantOncogenicity) {
+		t.Errorf("Oncogenicity = %+v, want %+v", tp53.Oncogenicity, wantOncogenicity)
+	}
+
+	//RCVClassifications
+	if len(tp53.RCVData) != 1 {
+		t.Fatalf("got %d RCVs, want 1", len(tp53.RCVData))
+	}
+	rcv := tp53.RCVData[0]
+	if rcv.AccessionID != "RCV000013171" || rcv.Interpretation != "Pathogenic" || rcv.ReviewStatus != "reviewed by expert panel" || rcv.MedGenID != "C0085390" {
+		t.Errorf("RCV = %+v", rcv)
+	}
+
+	//SCV Classification: one germline, one somatic clinical impact and one oncogenicity submission
+	if len(tp53.ClinicalAssertions) != 3 {
+		t.Fatalf("got %d SCVs, want 3", len(tp53.ClinicalAssertions))
+	}
+	germline, somatic, oncogenic := tp53.ClinicalAssertions[0], tp53.ClinicalAssertions[1], tp53.ClinicalAssertions[2]
+	if germline.Interpretation != "Pathogenic" || germline.ReviewStatus != "reviewed by expert panel" || germline.DateLastEvaluated != "2020-01-01" {
+		t.Errorf("germline SCV = %q, %q, %q", germline.Interpretation, germline.ReviewStatus, germline.DateLastEvaluated)
+	}
+	if somatic.SomaticClinicalImpact == nil || *somatic.SomaticClinicalImpact != wantImpact.Tiers[0] {
+		t.Errorf("somatic SCV SomaticClinicalImpact = %+v, want %+v", somatic.SomaticClinicalImpact, wantImpact.Tiers[0])
+	}
+	if oncogenic.Oncogenicity != "Oncogenic" || oncogenic.SomaticClinicalImpact != nil {
+		t.Errorf("oncogenicity SCV = %q, %+v", oncogenic.Oncogenicity, oncogenic.SomaticClinicalImpact)
+	}
+
+	//Without a germline classification the oncogenicity review status comes before the somatic one
+	braf := variants[1]
+	if braf.Interpretation != "" || braf.SomaticClinicalImpact == nil || braf.Oncogenicity == nil || braf.Oncogenicity.Description != "Oncogenic" {
+		t.Errorf("record without germline classification = %q, %+v, %+v", braf.Interpretation, braf.SomaticClinicalImpact, braf.Oncogenicity)
+	}
+	if braf.ReviewStatus != "criteria provided, single submitter" {
+		t.Errorf("ReviewStatus = %q, want the oncogenicity review status", braf.ReviewStatus)
+	}
+
+	//A record with only a somatic clinical impact
+	egfr := variants[2]
+	if egfr.Interpretation != "" || egfr.Oncogenicity != nil || egfr.SomaticClinicalImpact == nil {
+		t.Errorf("somatic-only record = %q, %+v, %+v", egfr.Interpretation, egfr.SomaticClinicalImpact, egfr.Oncogenicity)
+	}
+	if egfr.ReviewStatus != "criteria provided, single submitter" {
+		t.Errorf("somatic-only ReviewStatus = %q, want the somatic clinical impact review status", egfr.ReviewStatus)
+	}
+}
+
+func TestSchema2ParseMatchesReader(t *testing.T) {
+	_, streamed := readExample(t, schema2Example)
+	file, err := Open(schema2Example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	data, err := Parse(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed := data.ExtractAll(DefaultAssembly); !reflect.DeepEqual(parsed, streamed) {
+		t.Errorf("Parse and Reader disagree:\n%+v\n%+v", parsed, streamed)
+	}
+}
+
+func TestUnsupportedSchemaVersion(t *testing.T) {
+	release := `<ClinVarVariationRelease xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" ` +
+		`xsi:noNamespaceSchemaLocation="http://ftp.ncbi.nlm.nih.gov/pub/clinvar/xsd_public/ClinVar_VCV_3.0.xsd" ReleaseDate="2030-01-01"></ClinVarVariationRelease>`
+	if _, err := NewReader(strings.NewReader(release)); err == nil {
+		t.Error("NewReader accepted a schema 3.0 release")
+	}
+}
@@ -0,0 +1,70 @@
+package clinvar
+
+import (
+	"strconv"
+)
+
+// AlleleFrequency is the frequency of the variant allele reported by one population source.
+// SourceKey is a short name for the well-known sources (gnomAD_exomes, ExAC, 1000Genomes, ...)
+type AlleleFrequency struct {
+	Source    string
+	SourceKey string
+	Value     float64
+}
+
+// GlobalMinorAlleleFrequency is dbSNP's GMAF; the minor allele is not necessarily the variant allele
+type GlobalMinorAlleleFrequency struct {
+	Source      string
+	Value       float64
+	MinorAllele string
+}
+
+// alleleFrequencySourceKeys shortens the source names ClinVar uses in AlleleFrequencyList
+var alleleFrequencySourceKeys = map[string]string{
+	"The Genome Aggregation Database (gnomAD)":                  "gnomAD_genomes",
+	"The Genome Aggregation Database (gnomAD), exomes":          "gnomAD_exomes",
+	"Exome Aggregation Consortium (ExAC)":                       "ExAC",
+	"1000 Genomes Project":                                      "1000Genomes",
+	"NHLBI Exome Sequencing Project (ESP) Exome Variant Server": "GO-ESP",
+	"Trans-Omics for Precision Medicine (TOPMed)":               "TOPMed",
+	"Tohoku Medical Megabank Organization (ToMMo)":              "TOMMO",
+	"Tohoku Medical Megabank Organization":                      "TOMMO",
+}
+
+func alleleFrequencySourceKey(source string) string {
+	if key, ok := alleleFrequencySourceKeys[source]; ok {
+		return key
+	}
+	return source
+}
+
+// extractAlleleFrequencies parses the population frequencies of the variant as floats and
+// returns the highest of them, skipping values that are not numbers
+func (variant *VariationArchive) extractAlleleFrequencies() ([]AlleleFrequency, float64) {
+	frequencies := []AlleleFrequency{}
+	maxFrequency := 0.0
+	for _, frequency := range variant.simpleAllele().AlleleFrequencyList.AlleleFrequency {
+		value, err := strconv.ParseFloat(frequency.Value, 64)
+		if err != nil {
+			continue
+		}
+		frequencies = append(frequencies, AlleleFrequency{
+			Source:    frequency.Source,
+			SourceKey: alleleFrequencySourceKey(frequency.Source),
+			Value:     value})
+		if value > maxFrequency {
+			maxFrequency = value
+		}
+	}
+	return frequencies, maxFrequency
+}
+
+// extractGlobalMinorAlleleFrequency returns nil when the variant has no usable GMAF
+func (variant *VariationArchive) extractGlobalMinorAlleleFrequency() *GlobalMinorAlleleFrequency {
+	gmaf := variant.simpleAllele().GlobalMinorAlleleFrequency
+	value, err := strconv.ParseFloat(gmaf.Value, 64)
+	if err != nil {
+		return nil
+	}
+	return &GlobalMinorAlleleFrequency{Source: gmaf.Source, Value: value, MinorAllele: gmaf.MinorAllele}
+}
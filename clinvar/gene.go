@@ -0,0 +1,106 @@
+package clinvar
+
+import "strings"
+
+// Gene is one gene from the variant's GeneList. Large deletions and CNVs can list many genes
+type Gene struct {
+	Symbol              string
+	FullName            string
+	GeneID              string
+	HGNCID              string
+	OMIM                string
+	RelationshipType    string
+	Source              string
+	CytogeneticLocation string
+	Locations           []GeneLocation
+	Haploinsufficiency  DosageSensitivity
+	Triplosensitivity   DosageSensitivity
+}
+
+// DosageSensitivity is a ClinGen haploinsufficiency or triplosensitivity assessment of a gene
+type DosageSensitivity struct {
+	Score         string
+	LastEvaluated string
+	ClinGen       string
+}
+
+// extractGenes keeps every gene of the GeneList together with its per-assembly coordinates,
+// classifying the variant against each gene location
+func (variant *VariationArchive) extractGenes(variantLocations []VariantLocation) []Gene {
+	genes := []Gene{}
+	for _, gene := range variant.simpleAllele().GeneList.Gene {
+		ng := Gene{
+			Symbol:              gene.Symbol,
+			FullName:            gene.FullName,
+			GeneID:              gene.GeneID,
+			HGNCID:              gene.HGNCID,
+			OMIM:                gene.OMIM,
+			RelationshipType:    gene.RelationshipType,
+			Source:              gene.Source,
+			CytogeneticLocation: gene.Location.CytogeneticLocation,
+			Locations:           []GeneLocation{},
+			Haploinsufficiency: DosageSensitivity{
+				Score:         gene.Haploinsufficiency.Text,
+				LastEvaluated: gene.Haploinsufficiency.LastEvaluated,
+				ClinGen:       gene.Haploinsufficiency.ClinGen},
+			Triplosensitivity: DosageSensitivity{
+				Score:         gene.Triplosensitivity.Text,
+				LastEvaluated: gene.Triplosensitivity.LastEvaluated,
+				ClinGen:       gene.Triplosensitivity.ClinGen},
+		}
+		for _, geneSequence := range gene.Location.SequenceLocation {
+			ng.Locations = append(ng.Locations, locateGene(GeneLocation{
+				Assembly:  geneSequence.Assembly,
+				Chr:       geneSequence.Chr,
+				Accession: geneSequence.Accession,
+				Start:     parseCoordinate(geneSequence.Start),
+				Stop:      parseCoordinate(geneSequence.Stop),
+				Strand:    geneSequence.Strand}, variantLocations))
+		}
+		genes = append(genes, ng)
+	}
+	return genes
+}
+
+// GeneSet holds the genes a release is filtered on, each given as a symbol, NCBI Gene ID or HGNC ID
+// (e.g. "BRCA1", "672" or "HGNC:1100"). Matching ignores case
+type GeneSet map[string]bool
+
+// NewGeneSet returns the set of the given genes
+func NewGeneSet(genes ...string) GeneSet {
+	set := GeneSet{}
+	for _, gene := range genes {
+		set.Add(gene)
+	}
+	return set
+}
+
+// Add puts a gene symbol or ID in the set
+func (set GeneSet) Add(gene string) {
+	set[strings.ToUpper(strings.TrimSpace(gene))] = true
+}
+
+// containsGeneOf reports whether any gene of the allele's GeneList is in the set
+func (set GeneSet) containsGeneOf(allele *SimpleAllele) bool {
+	for _, gene := range allele.GeneList.Gene {
+		for _, id := range []string{gene.Symbol, gene.GeneID, gene.HGNCID} {
+			if id != "" && set[strings.ToUpper(id)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasGene reports whether a gene of the variant's GeneList, or of the GeneList of one of its member
+// alleles, is in genes. It only reads the decoded archive, so variants can be filtered before Extract
+func (variant *VariationArchive) HasGene(genes GeneSet) bool {
+	if genes.containsGeneOf(variant.simpleAllele()) {
+		return true
+	}
+	found := false
+	variant.eachMemberAllele(func(allele *SimpleAllele, haplotypeVariationID string) {
+		found = found || genes.containsGeneOf(allele)
+	})
+	return found
+}
@@ -0,0 +1,48 @@
+package clinvar
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+// decodeVariationArchive decodes a VariationArchive element written inline in a test
+func decodeVariationArchive(t *testing.T, element string) *VariationArchive {
+	t.Helper()
+	var variant VariationArchive
+	if err := xml.Unmarshal([]byte(element), &variant); err != nil {
+		t.Fatal(err)
+	}
+	return &variant
+}
+
+func TestHasGene(t *testing.T) {
+	simple := decodeVariationArchive(t, `<VariationArchive Accession="VCV1" RecordType="interpreted"><InterpretedRecord>
+		<SimpleAllele><GeneList><Gene Symbol="TP53" GeneID="7157" HGNC_ID="HGNC:11998"/></GeneList></SimpleAllele>
+	</InterpretedRecord></VariationArchive>`)
+	haplotype := decodeVariationArchive(t, `<VariationArchive Accession="VCV2" RecordType="interpreted"><InterpretedRecord><Haplotype>
+		<SimpleAllele><GeneList><Gene Symbol="CFTR" GeneID="1080" HGNC_ID="HGNC:1884"/></GeneList></SimpleAllele>
+		<SimpleAllele><GeneList><Gene Symbol="BRCA1" GeneID="672" HGNC_ID="HGNC:1100"/></GeneList></SimpleAllele>
+	</Haplotype></InterpretedRecord></VariationArchive>`)
+
+	for _, tc := range []struct {
+		name    string
+		variant *VariationArchive
+		genes   GeneSet
+		want    bool
+	}{
+		{"symbol", simple, NewGeneSet("TP53"), true},
+		{"symbol in another case", simple, NewGeneSet("tp53"), true},
+		{"Gene ID", simple, NewGeneSet("7157"), true},
+		{"HGNC ID", simple, NewGeneSet("hgnc:11998"), true},
+		{"other genes", simple, NewGeneSet("BRCA1", "672", "HGNC:1100"), false},
+		{"empty set", simple, NewGeneSet(), false},
+		{"second member allele by symbol", haplotype, NewGeneSet("BRCA1"), true},
+		{"member allele by Gene ID", haplotype, NewGeneSet("1080"), true},
+		{"member allele by HGNC ID", haplotype, NewGeneSet("HGNC:1884"), true},
+		{"no member allele", haplotype, NewGeneSet("TP53"), false},
+	} {
+		if got := tc.variant.HasGene(tc.genes); got != tc.want {
+			t.Errorf("%s: HasGene = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
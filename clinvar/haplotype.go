@@ -0,0 +1,143 @@
+package clinvar
+
+// Haplotype is a set of SimpleAlleles observed together on one chromosome
+type Haplotype struct {
+	Text                string         `xml:",chardata"`
+	VariationID         string         `xml:"VariationID,attr"`
+	NumberOfChromosomes string         `xml:"NumberOfChromosomes,attr"`
+	SimpleAllele        []SimpleAllele `xml:"SimpleAllele"`
+	Name                string         `xml:"Name"`
+	VariationType       string         `xml:"VariationType"`
+}
+
+// Genotype is a combination of alleles and haplotypes on both chromosomes,
+// such as a CompoundHeterozygote or Diplotype
+type Genotype struct {
+	Text          string         `xml:",chardata"`
+	VariationID   string         `xml:"VariationID,attr"`
+	SimpleAllele  []SimpleAllele `xml:"SimpleAllele"`
+	Haplotype     []Haplotype    `xml:"Haplotype"`
+	Name          string         `xml:"Name"`
+	VariationType string         `xml:"VariationType"`
+}
+
+// IncludedRecord replaces InterpretedRecord for variants that ClinVar only describes
+// as part of other (haplotype or genotype) records
+type IncludedRecord struct {
+	Text                     string        `xml:",chardata"`
+	SimpleAllele             *SimpleAllele `xml:"SimpleAllele"`
+	Haplotype                *Haplotype    `xml:"Haplotype"`
+	Genotype                 *Genotype     `xml:"Genotype"`
+	ReviewStatus             string        `xml:"ReviewStatus"`
+	InterpretedVariationList struct {
+		Text                 string `xml:",chardata"`
+		InterpretedVariation []struct {
+			Text        string `xml:",chardata"`
+			VariationID string `xml:"VariationID,attr"`
+			Accession   string `xml:"Accession,attr"`
+			Version     string `xml:"Version,attr"`
+		} `xml:"InterpretedVariation"`
+	} `xml:"InterpretedVariationList"`
+}
+
+// MemberAllele is a SimpleAllele that is part of a Haplotype or Genotype record, linked back to
+// the parent VCV. HaplotypeVariationID is set for alleles of a haplotype nested in a genotype
+type MemberAllele struct {
+	ParentAccession      string
+	ParentVariationID    string
+	HaplotypeVariationID string
+	AlleleID             string
+	VariationID          string
+	Name                 string
+	VariantType          string
+	CanonicalSPDI        string
+	DbSNPID              string
+	GeneSymbols          []string
+	Locations            []VariantLocation
+}
+
+// simpleAllele returns the allele the flat variant fields are extracted from: the interpreted
+// SimpleAllele, or the included one for IncludedRecord archives. Haplotype and Genotype records
+// have no such allele and return an empty one
+func (variant *VariationArchive) simpleAllele() *SimpleAllele {
+	if variant.IncludedRecord != nil && variant.IncludedRecord.SimpleAllele != nil {
+		return variant.IncludedRecord.SimpleAllele
+	}
+	return &variant.record().SimpleAllele
+}
+
+// eachMemberAllele calls visit with every SimpleAllele of Haplotype and Genotype records, whether interpreted
+// or included. haplotypeVariationID is set for alleles of a haplotype nested in a genotype
+func (variant *VariationArchive) eachMemberAllele(visit func(allele *SimpleAllele, haplotypeVariationID string)) {
+	haplotype, genotype := variant.record().Haplotype, variant.record().Genotype
+	if variant.IncludedRecord != nil {
+		if variant.IncludedRecord.Haplotype != nil {
+			haplotype = variant.IncludedRecord.Haplotype
+		}
+		if variant.IncludedRecord.Genotype != nil {
+			genotype = variant.IncludedRecord.Genotype
+		}
+	}
+
+	visitAll := func(alleles []SimpleAllele, haplotypeVariationID string) {
+		for i := range alleles {
+			visit(&alleles[i], haplotypeVariationID)
+		}
+	}
+	if haplotype != nil {
+		visitAll(haplotype.SimpleAllele, "")
+	}
+	if genotype != nil {
+		visitAll(genotype.SimpleAllele, "")
+		for _, nested := range genotype.Haplotype {
+			visitAll(nested.SimpleAllele, nested.VariationID)
+		}
+	}
+}
+
+// extractMemberAlleles lists the SimpleAlleles of Haplotype and Genotype records, whether interpreted or included
+func (variant *VariationArchive) extractMemberAlleles() []MemberAllele {
+	members := []MemberAllele{}
+	variant.eachMemberAllele(func(allele *SimpleAllele, haplotypeVariationID string) {
+		members = append(members, variant.memberAllele(allele, haplotypeVariationID))
+	})
+	return members
+}
+
+func (variant *VariationArchive) memberAllele(allele *SimpleAllele, haplotypeVariationID string) MemberAllele {
+	member := MemberAllele{
+		ParentAccession:      variant.Accession,
+		ParentVariationID:    variant.VariationID,
+		HaplotypeVariationID: haplotypeVariationID,
+		AlleleID:             allele.AlleleID,
+		VariationID:          allele.VariationID,
+		Name:                 allele.Name,
+		VariantType:          allele.VariantType,
+		CanonicalSPDI:        allele.CanonicalSPDI,
+		DbSNPID:              NotProvided,
+		GeneSymbols:          []string{},
+		Locations:            allele.extractLocations(),
+	}
+	for _, xref := range allele.XRefList.XRef {
+		if xref.DB == "dbSNP" {
+			member.DbSNPID = xref.ID
+			break
+		}
+	}
+	for _, gene := range allele.GeneList.Gene {
+		member.GeneSymbols = append(member.GeneSymbols, gene.Symbol)
+	}
+	return member
+}
+
+// extractIncludedIn lists the VCV accessions of the interpreted records an IncludedRecord is part of
+func (variant *VariationArchive) extractIncludedIn() []string {
+	includedIn := []string{}
+	if variant.IncludedRecord == nil {
+		return includedIn
+	}
+	for _, interpreted := range variant.IncludedRecord.InterpretedVariationList.InterpretedVariation {
+		includedIn = append(includedIn, interpreted.Accession)
+	}
+	return includedIn
+}
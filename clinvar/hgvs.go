@@ -0,0 +1,57 @@
+package clinvar
+
+// HGVSExpression is one HGVS description of the variant (coding, genomic, protein, ...)
+// with the molecular consequences ClinVar computed for it
+type HGVSExpression struct {
+	Type                     string
+	Assembly                 string
+	NucleotideExpression     string
+	SequenceAccessionVersion string
+	SequenceAccession        string
+	SequenceVersion          string
+	Change                   string
+	ProteinExpression        string
+	ProteinAccessionVersion  string
+	ProteinChange            string
+	MANESelect               bool
+	MolecularConsequences    []MolecularConsequence
+}
+
+// MolecularConsequence is a consequence term, with its Sequence Ontology ID and source DB
+type MolecularConsequence struct {
+	Type string
+	ID   string
+	DB   string
+}
+
+// extractHGVSExpressions keeps every HGVS entry of the variant, flagging the MANE Select transcript
+func (variant *VariationArchive) extractHGVSExpressions() []HGVSExpression {
+	variantAllHgvs := []HGVSExpression{}
+	for _, hgvs := range variant.simpleAllele().HGVSlist.HGVS {
+		nh := HGVSExpression{
+			Type:                     hgvs.Type,
+			Assembly:                 hgvs.Assembly,
+			NucleotideExpression:     hgvs.NucleotideExpression.Expression,
+			SequenceAccessionVersion: hgvs.NucleotideExpression.SequenceAccessionVersion,
+			SequenceAccession:        hgvs.NucleotideExpression.SequenceAccession,
+			SequenceVersion:          hgvs.NucleotideExpression.SequenceVersion,
+			Change:                   hgvs.NucleotideExpression.Change,
+			ProteinExpression:        hgvs.ProteinExpression.Expression,
+			ProteinAccessionVersion:  hgvs.ProteinExpression.SequenceAccessionVersion,
+			ProteinChange:            hgvs.ProteinExpression.Change,
+			MANESelect:               hgvs.NucleotideExpression.MANESelect == "true",
+			MolecularConsequences:    []MolecularConsequence{},
+		}
+		if nh.Assembly == "" {
+			nh.Assembly = hgvs.NucleotideExpression.Assembly
+		}
+		for _, consequence := range hgvs.MolecularConsequence {
+			nh.MolecularConsequences = append(nh.MolecularConsequences, MolecularConsequence{
+				Type: consequence.Type,
+				ID:   consequence.ID,
+				DB:   consequence.DB})
+		}
+		variantAllHgvs = append(variantAllHgvs, nh)
+	}
+	return variantAllHgvs
+}
@@ -0,0 +1,29 @@
+package clinvar
+
+// ClassificationHistory is an earlier aggregate classification of the variant and the date it was recorded
+type ClassificationHistory struct {
+	Date        string
+	Description string
+}
+
+// extractClassificationHistory keeps the DescriptionHistory of the aggregate germline classification
+func (variant *VariationArchive) extractClassificationHistory() []ClassificationHistory {
+	history := []ClassificationHistory{}
+	for _, description := range variant.record().germlineClassification().DescriptionHistory {
+		history = append(history, ClassificationHistory{
+			Date:        description.Dated,
+			Description: description.Description})
+	}
+	return history
+}
+
+// ClassificationChangedSince reports whether the aggregate classification changed after date (YYYY-MM-DD):
+// some classification recorded after that date differs from the current one
+func (variantInfo *ClinVarVariationData) ClassificationChangedSince(date string) bool {
+	for _, history := range variantInfo.ClassificationHistory {
+		if history.Date > date && history.Description != variantInfo.Interpretation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package clinvar
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// gzipMagic is the two byte header shared by gzip and bgzip (blocked gzip) files
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressedFile closes both the gzip stream and the underlying file
+type compressedFile struct {
+	*gzip.Reader
+	file io.Closer
+}
+
+func (c compressedFile) Close() error {
+	gzErr := c.Reader.Close()
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	return gzErr
+}
+
+// bufferedFile keeps the peeked bytes of an uncompressed file available to the XML decoder
+type bufferedFile struct {
+	*bufio.Reader
+	file io.Closer
+}
+
+func (b bufferedFile) Close() error {
+	return b.file.Close()
+}
+
+// Open opens a release file for reading, decompressing gzip and bgzip files on the fly
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	input, err := Decompress(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return input, nil
+}
+
+// IsCompressed reports whether the file starts with the gzip magic bytes
+func IsCompressed(file io.ReaderAt) (bool, error) {
+	header := make([]byte, len(gzipMagic))
+	n, err := file.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	return bytes.Equal(header[:n], gzipMagic), nil
+}
+
+// Decompress detects gzip magic bytes and transparently decompresses the input. Closing the returned
+// reader closes file. gzip.Reader reads multistream input by default, so multi-member bgzip files
+// such as ClinVarVariationRelease_00-latest.xml.gz are decoded member after member
+func Decompress(file io.ReadCloser) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(file)
+	header, err := buffered.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if !bytes.Equal(header, gzipMagic) {
+		return bufferedFile{Reader: buffered, file: file}, nil
+	}
+
+	gzReader, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	return compressedFile{Reader: gzReader, file: file}, nil
+}
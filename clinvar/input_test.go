@@ -0,0 +1,161 @@
+package clinvar
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const p53Example = "../ClinVarVariationRelease_p53Example.xml"
+
+// countVariants counts the VariationArchive elements of a release
+func countVariants(t *testing.T, r io.Reader) int {
+	t.Helper()
+	reader, err := NewReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for {
+		_, err := reader.Next()
+		if err == io.EOF {
+			return count
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+}
+
+// gzipMembers compresses each part as a gzip member of its own and concatenates them, as bgzip does
+func gzipMembers(t *testing.T, parts ...[]byte) []byte {
+	t.Helper()
+	var compressed bytes.Buffer
+	for _, part := range parts {
+		gzWriter := gzip.NewWriter(&compressed)
+		if _, err := gzWriter.Write(part); err != nil {
+			t.Fatal(err)
+		}
+		if err := gzWriter.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return compressed.Bytes()
+}
+
+func TestOpenCompressedReleases(t *testing.T) {
+	plain, err := os.ReadFile(p53Example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := []struct {
+		name       string
+		content    []byte
+		compressed bool
+	}{
+		{"plain.xml", plain, false},
+		{"single.xml.gz", gzipMembers(t, plain), true},
+		{"members.xml.gz", gzipMembers(t, plain[:len(plain)/2], plain[len(plain)/2:]), true},
+	}
+
+	want := countVariants(t, bytes.NewReader(plain))
+	if want == 0 {
+		t.Fatalf("%s has no variants", p53Example)
+	}
+	for _, file := range files {
+		path := filepath.Join(dir, file.name)
+		if err := os.WriteFile(path, file.content, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		osFile, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		compressed, err := IsCompressed(osFile)
+		osFile.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if compressed != file.compressed {
+			t.Errorf("%s: IsCompressed = %v, want %v", file.name, compressed, file.compressed)
+		}
+
+		input, err := Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(input)
+		input.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Errorf("%s: decompressed %d bytes, want the %d bytes of the plain release", file.name, len(got), len(plain))
+		}
+
+		input, err = Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count := countVariants(t, input); count != want {
+			t.Errorf("%s: %d variants, want %d", file.name, count, want)
+		}
+		input.Close()
+	}
+}
+
+func TestDecompressStdin(t *testing.T) {
+	plain, err := os.ReadFile(p53Example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//A pipe can't be read at an offset or rewound, like stdin
+	for _, content := range [][]byte{plain, gzipMembers(t, plain[:len(plain)/3], plain[len(plain)/3:])} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			w.Write(content)
+			w.Close()
+		}()
+		input, err := Decompress(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, plain) {
+			t.Errorf("decompressed %d bytes from a pipe, want the %d bytes of the plain release", len(got), len(plain))
+		}
+		if err := input.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Read(make([]byte, 1)); err == nil {
+			t.Error("closing the decompressed input left the pipe open")
+		}
+	}
+}
+
+func TestIsCompressedEmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.xml")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if compressed, err := IsCompressed(file); err != nil || compressed {
+		t.Errorf("IsCompressed of an empty file = %v, %v, want false, nil", compressed, err)
+	}
+}
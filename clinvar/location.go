@@ -0,0 +1,140 @@
+package clinvar
+
+import (
+	"strconv"
+)
+
+// Variant location relative to a gene, as reported in LocationType
+const (
+	withinGene   = "withinGene"
+	overlapsGene = "overlapsGene"
+	upstream     = "upstream"
+	downstream   = "downstream"
+)
+
+// NotProvided fills the text fields ClinVar gives no value for, and LocationType when there is no location to compare
+const NotProvided = "notProvided"
+
+// GeneLocation is a gene's span on one assembly and the variant's position relative to it, as classifyGeneLocation reports it
+type GeneLocation struct {
+	Assembly       string
+	Chr            string
+	Accession      string
+	Start          int
+	Stop           int
+	Strand         string
+	LocationType   string
+	DistanceToGene int
+}
+
+// parseCoordinate parses a 1-based coordinate attribute; 0 means the attribute was missing or malformed
+func parseCoordinate(value string) int {
+	coordinate, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return coordinate
+}
+
+// coordinateString renders a parsed coordinate for the legacy string fields
+func coordinateString(coordinate int) string {
+	if coordinate == 0 {
+		return NotProvided
+	}
+	return strconv.Itoa(coordinate)
+}
+
+// extractLocations parses every SequenceLocation of the allele, one per assembly
+func (allele *SimpleAllele) extractLocations() []VariantLocation {
+	variantLocations := []VariantLocation{}
+	for _, location := range allele.Location.SequenceLocation {
+		length := location.Length
+		if length == "" {
+			length = location.VariantLength
+		}
+		variantLocations = append(variantLocations, VariantLocation{
+			Assembly:           location.Assembly,
+			Chr:                location.Chr,
+			Accession:          location.Accession,
+			Start:              parseCoordinate(location.Start),
+			Stop:               parseCoordinate(location.Stop),
+			DisplayStart:       parseCoordinate(location.DisplayStart),
+			DisplayStop:        parseCoordinate(location.DisplayStop),
+			Length:             parseCoordinate(length),
+			PositionVCF:        parseCoordinate(location.PositionVCF),
+			ReferenceAlleleVCF: location.ReferenceAlleleVCF,
+			AlternateAlleleVCF: location.AlternateAlleleVCF})
+	}
+	return variantLocations
+}
+
+// classifyGeneLocation compares a variant span with a gene span on the same assembly and chromosome.
+// Upstream and downstream follow the gene's strand, so a variant past the stop of a minus-strand gene is upstream
+func classifyGeneLocation(variantStart, variantStop, geneStart, geneStop int, strand string) (string, int) {
+	switch {
+	case variantStart >= geneStart && variantStop <= geneStop:
+		distance := variantStart - geneStart
+		if geneStop-variantStop < distance {
+			distance = geneStop - variantStop
+		}
+		return withinGene, distance
+	case variantStop < geneStart:
+		if strand == "-" {
+			return downstream, geneStart - variantStop
+		}
+		return upstream, geneStart - variantStop
+	case variantStart > geneStop:
+		if strand == "-" {
+			return upstream, variantStart - geneStop
+		}
+		return downstream, variantStart - geneStop
+	}
+	return overlapsGene, 0
+}
+
+// locateGene records the gene's span on the given assembly and classifies the variant against it
+// when the variant has a location on the same assembly and chromosome
+func locateGene(geneLocation GeneLocation, variantLocations []VariantLocation) GeneLocation {
+	geneLocation.LocationType = NotProvided
+	if geneLocation.Start == 0 || geneLocation.Stop == 0 {
+		return geneLocation
+	}
+	for _, location := range variantLocations {
+		if location.Assembly != geneLocation.Assembly || location.Chr != geneLocation.Chr || location.Start == 0 || location.Stop == 0 {
+			continue
+		}
+		geneLocation.LocationType, geneLocation.DistanceToGene = classifyGeneLocation(location.Start, location.Stop,
+			geneLocation.Start, geneLocation.Stop, geneLocation.Strand)
+		break
+	}
+	return geneLocation
+}
+
+// locationTypeRank orders location types from the closest relationship to a gene to the loosest
+var locationTypeRank = map[string]int{withinGene: 0, overlapsGene: 1, upstream: 2, downstream: 2}
+
+// summarizeLocationType picks the legacy LocationType for an assembly: the closest relationship to any
+// gene, with ties between upstream and downstream going to the nearest gene
+func summarizeLocationType(genes []Gene, assembly string) string {
+	var best *GeneLocation
+	for g := range genes {
+		for l := range genes[g].Locations {
+			geneLocation := &genes[g].Locations[l]
+			if geneLocation.Assembly != assembly || geneLocation.LocationType == NotProvided {
+				continue
+			}
+			if best == nil {
+				best = geneLocation
+				continue
+			}
+			rank, bestRank := locationTypeRank[geneLocation.LocationType], locationTypeRank[best.LocationType]
+			if rank < bestRank || (rank == bestRank && rank == 2 && geneLocation.DistanceToGene < best.DistanceToGene) {
+				best = geneLocation
+			}
+		}
+	}
+	if best == nil {
+		return NotProvided
+	}
+	return best.LocationType
+}
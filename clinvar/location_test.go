@@ -0,0 +1,37 @@
+package clinvar
+
+import "testing"
+
+func TestClassifyGeneLocation(t *testing.T) {
+	//The gene spans 100-200 on both strands
+	for _, tc := range []struct {
+		name                      string
+		variantStart, variantStop int
+		strand                    string
+		wantType                  string
+		wantDistance              int
+	}{
+		{"inside", 140, 150, "+", withinGene, 40},
+		{"inside nearer the stop", 180, 190, "+", withinGene, 10},
+		{"on the first gene base", 100, 100, "+", withinGene, 0},
+		{"on the last gene base", 200, 200, "-", withinGene, 0},
+		{"spanning exactly the gene", 100, 200, "+", withinGene, 0},
+		{"spanning the whole gene and more", 90, 210, "+", overlapsGene, 0},
+		{"overlapping the start", 95, 105, "+", overlapsGene, 0},
+		{"overlapping the stop", 195, 205, "-", overlapsGene, 0},
+		{"adjacent before the start, plus strand", 99, 99, "+", upstream, 1},
+		{"adjacent before the start, minus strand", 99, 99, "-", downstream, 1},
+		{"adjacent after the stop, plus strand", 201, 201, "+", downstream, 1},
+		{"adjacent after the stop, minus strand", 201, 201, "-", upstream, 1},
+		{"before the start, plus strand", 40, 50, "+", upstream, 50},
+		{"before the start, minus strand", 40, 50, "-", downstream, 50},
+		{"after the stop, plus strand", 250, 260, "+", downstream, 50},
+		{"after the stop, minus strand", 250, 260, "-", upstream, 50},
+		{"unknown strand is treated as plus", 40, 50, "", upstream, 50},
+	} {
+		gotType, gotDistance := classifyGeneLocation(tc.variantStart, tc.variantStop, 100, 200, tc.strand)
+		if gotType != tc.wantType || gotDistance != tc.wantDistance {
+			t.Errorf("%s: got %s %d, want %s %d", tc.name, gotType, gotDistance, tc.wantType, tc.wantDistance)
+		}
+	}
+}
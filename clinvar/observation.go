@@ -0,0 +1,57 @@
+package clinvar
+
+import (
+	"strconv"
+)
+
+// Observation is one ObservedIn entry of a submitted clinical assertion: the sample the
+// variant was seen in and the method used to observe it
+type Observation struct {
+	Origin         string
+	Species        string
+	TaxonomyID     string
+	AffectedStatus string
+	NumberTested   int
+	MethodType     string
+	TypePlatform   string
+	ObservedData   []ObservedDataAttribute
+}
+
+// ObservedDataAttribute is an observed-data attribute such as Description or VariantAlleles
+type ObservedDataAttribute struct {
+	Type         string
+	Value        string
+	IntegerValue int
+}
+
+// ObservationSummary totals the observations of all SCVs of a variant. OriginCounts counts
+// observations per allele origin (germline, somatic, de novo, ...), so germline and tumor
+// pipelines can be separated
+type ObservationSummary struct {
+	OriginCounts map[string]int
+	NumberTested int
+}
+
+// parseCount parses a count attribute, treating missing or malformed values as 0
+func parseCount(value string) int {
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+func summarizeObservations(assertions []ClinicalAssertions) ObservationSummary {
+	summary := ObservationSummary{OriginCounts: make(map[string]int)}
+	for _, assertion := range assertions {
+		for _, observation := range assertion.Observations {
+			origin := observation.Origin
+			if origin == "" {
+				origin = NotProvided
+			}
+			summary.OriginCounts[origin]++
+			summary.NumberTested += observation.NumberTested
+		}
+	}
+	return summary
+}
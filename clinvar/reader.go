@@ -0,0 +1,143 @@
+package clinvar
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+)
+
+// DefaultAssembly is the assembly whose location populates the legacy flat location fields unless another is chosen
+const DefaultAssembly = "GRCh38"
+
+// Reader decodes the VariationArchive elements of a release one at a time, so full-size ClinVar
+// releases can be processed without holding every record in memory
+type Reader struct {
+	xmlDecoder  *xml.Decoder
+	releaseInfo ClinVarDataReleaseInfo
+	offset      int64
+}
+
+// NewReader reads the release's root element, checking that it is a ClinVar variation release
+// of a supported schema version
+func NewReader(r io.Reader) (*Reader, error) {
+	xmlDecoder := xml.NewDecoder(r)
+	for {
+		token, err := xmlDecoder.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no release root element: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !isReleaseRootElement(start.Name.Local) {
+			return nil, fmt.Errorf("unexpected root element %q", start.Name.Local)
+		}
+		releaseInfo := extractReleaseInfoFromRoot(start)
+		if err := checkSchemaVersion(releaseInfo); err != nil {
+			return nil, err
+		}
+		return &Reader{xmlDecoder: xmlDecoder, releaseInfo: releaseInfo}, nil
+	}
+}
+
+// ReleaseInfo returns the schema and release date read from the root element
+func (reader *Reader) ReleaseInfo() ClinVarDataReleaseInfo {
+	return reader.releaseInfo
+}
+
+// Next decodes the next VariationArchive. It returns io.EOF once the release has no more
+func (reader *Reader) Next() (*VariationArchive, error) {
+	for {
+		offset := reader.xmlDecoder.InputOffset()
+		token, err := reader.xmlDecoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "VariationArchive" {
+			continue
+		}
+		var variant VariationArchive
+		if err := reader.xmlDecoder.DecodeElement(&variant, &start); err != nil {
+			return nil, err
+		}
+		reader.offset = offset
+		return &variant, nil
+	}
+}
+
+// Offset returns the byte offset in the (decompressed) input at which the VariationArchive last
+// returned by Next starts, for use with ReadVariationArchiveAt
+func (reader *Reader) Offset() int64 {
+	return reader.offset
+}
+
+// ReadVariationArchiveAt decodes the VariationArchive starting at offset in an uncompressed release,
+// as reported by Reader.Offset
+func ReadVariationArchiveAt(r io.ReaderAt, offset int64) (*VariationArchive, error) {
+	xmlDecoder := xml.NewDecoder(io.NewSectionReader(r, offset, math.MaxInt64-offset))
+	token, err := xmlDecoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	start, ok := token.(xml.StartElement)
+	if !ok || start.Name.Local != "VariationArchive" {
+		return nil, fmt.Errorf("no VariationArchive at offset %d", offset)
+	}
+	var variant VariationArchive
+	if err := xmlDecoder.DecodeElement(&variant, &start); err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// Variants extracts each variant of the reader on the given assembly as it is decoded. Decode errors are
+// yielded once and end the sequence. The sequence consumes the reader, so it can only be ranged over once
+func (reader *Reader) Variants(assembly string) iter.Seq2[ClinVarVariationData, error] {
+	return func(yield func(ClinVarVariationData, error) bool) {
+		for {
+			variant, err := reader.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(ClinVarVariationData{}, err)
+				return
+			}
+			if !yield(Extract(variant, assembly), nil) {
+				return
+			}
+		}
+	}
+}
+
+// Variants lazily yields the extracted variants of a release, with DefaultAssembly populating the flat
+// location fields:
+//
+//	for variantInfo, err := range clinvar.Variants(file) {
+//		...
+//	}
+//
+// When r is an io.Closer it is closed once the loop ends, whether the release was read to the end,
+// an error was yielded or the loop broke early
+func Variants(r io.Reader) iter.Seq2[ClinVarVariationData, error] {
+	return func(yield func(ClinVarVariationData, error) bool) {
+		if closer, ok := r.(io.Closer); ok {
+			defer closer.Close()
+		}
+		reader, err := NewReader(r)
+		if err != nil {
+			yield(ClinVarVariationData{}, err)
+			return
+		}
+		reader.Variants(DefaultAssembly)(yield)
+	}
+}
@@ -0,0 +1,98 @@
+package clinvar
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// closeRecorder is a release that records whether it was closed
+type closeRecorder struct {
+	*bytes.Reader
+	closed int
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed++
+	return nil
+}
+
+func newCloseRecorder(t *testing.T, path string) *closeRecorder {
+	t.Helper()
+	release, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &closeRecorder{Reader: bytes.NewReader(release)}
+}
+
+func TestVariantsClosesAfterBreak(t *testing.T) {
+	release := newCloseRecorder(t, p53Example)
+	seen := 0
+	for _, err := range Variants(release) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen++
+		if release.closed != 0 {
+			t.Fatal("release closed while the loop is still running")
+		}
+		break
+	}
+	if seen != 1 {
+		t.Errorf("loop ran %d times, want 1", seen)
+	}
+	if release.closed != 1 {
+		t.Errorf("release closed %d times after break, want 1", release.closed)
+	}
+}
+
+func TestVariantsClosesAtEnd(t *testing.T) {
+	release := newCloseRecorder(t, p53Example)
+	seen := 0
+	for _, err := range Variants(release) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen++
+	}
+	if want := countVariants(t, newCloseRecorder(t, p53Example)); seen != want {
+		t.Errorf("got %d variants, want %d", seen, want)
+	}
+	if release.closed != 1 {
+		t.Errorf("release closed %d times, want 1", release.closed)
+	}
+}
+
+func TestVariantsYieldsDecodeError(t *testing.T) {
+	content, err := os.ReadFile(p53Example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := countVariants(t, bytes.NewReader(content))
+
+	//Cut the release inside its last VariationArchive
+	last := bytes.LastIndex(content, []byte("<VariationArchive "))
+	release := &closeRecorder{Reader: bytes.NewReader(content[:last+200])}
+
+	seen, errs := 0, 0
+	for variantInfo, err := range Variants(release) {
+		if errs > 0 {
+			t.Fatalf("yielded %+v after the decode error", variantInfo)
+		}
+		if err != nil {
+			errs++
+			continue
+		}
+		seen++
+	}
+	if errs != 1 {
+		t.Errorf("yielded %d errors, want 1", errs)
+	}
+	if seen != want-1 {
+		t.Errorf("got %d variants before the error, want %d", seen, want-1)
+	}
+	if release.closed != 1 {
+		t.Errorf("release closed %d times after the error, want 1", release.closed)
+	}
+}
@@ -0,0 +1,136 @@
+package clinvar
+
+import (
+	"sort"
+	"strings"
+)
+
+// Region is a span of a chromosome in 1-based, inclusive coordinates, as ClinVar reports locations
+type Region struct {
+	Chr   string
+	Start int
+	Stop  int
+}
+
+// RegionSet answers whether a span overlaps any of many regions, with an interval tree per chromosome
+// so that thousands of regions (e.g. an exome capture kit) stay fast. Chromosomes are compared without
+// a "chr" prefix, so "chr17" matches ClinVar's "17" and "chrM" matches "MT"
+type RegionSet struct {
+	trees map[string]*intervalTree
+}
+
+// NewRegionSet returns the set of the given regions
+func NewRegionSet(regions []Region) *RegionSet {
+	byChr := make(map[string][]Region)
+	for _, region := range regions {
+		chr := normalizeChr(region.Chr)
+		byChr[chr] = append(byChr[chr], region)
+	}
+	set := &RegionSet{trees: make(map[string]*intervalTree)}
+	for chr, chrRegions := range byChr {
+		set.trees[chr] = newIntervalTree(chrRegions)
+	}
+	return set
+}
+
+// Overlaps reports whether the span from start to stop (1-based, inclusive) on chr overlaps a region of the set
+func (set *RegionSet) Overlaps(chr string, start, stop int) bool {
+	tree, ok := set.trees[normalizeChr(chr)]
+	return ok && tree.overlaps(0, len(tree.regions), start, stop)
+}
+
+// overlapsAny reports whether a location of the allele on the assembly overlaps a region of the set.
+// Locations without start and stop, such as those of some CNVs, fall back to their display coordinates
+func (set *RegionSet) overlapsAny(allele *SimpleAllele, assembly string) bool {
+	for _, location := range allele.extractLocations() {
+		if location.Assembly != assembly {
+			continue
+		}
+		start, stop := location.Start, location.Stop
+		if start == 0 || stop == 0 {
+			start, stop = location.DisplayStart, location.DisplayStop
+		}
+		if start != 0 && stop != 0 && set.Overlaps(location.Chr, start, stop) {
+			return true
+		}
+	}
+	return false
+}
+
+// InRegions reports whether the variant's location on the assembly, or that of one of its member alleles,
+// overlaps a region of the set. Like HasGene it only reads the decoded archive, so it can filter before Extract
+func (variant *VariationArchive) InRegions(regions *RegionSet, assembly string) bool {
+	if regions.overlapsAny(variant.simpleAllele(), assembly) {
+		return true
+	}
+	found := false
+	variant.eachMemberAllele(func(allele *SimpleAllele, haplotypeVariationID string) {
+		found = found || regions.overlapsAny(allele, assembly)
+	})
+	return found
+}
+
+// normalizeChr strips the UCSC "chr" prefix and names the mitochondrial chromosome MT, as ClinVar does
+func normalizeChr(chr string) string {
+	if len(chr) > 3 && strings.EqualFold(chr[:3], "chr") {
+		chr = chr[3:]
+	}
+	if strings.EqualFold(chr, "M") {
+		return "MT"
+	}
+	return strings.ToUpper(chr)
+}
+
+// intervalTree is a static augmented interval tree over the regions of one chromosome. The regions are sorted
+// by start and the middle of each range of them is the root of the subtree for that range, with maxStop
+// holding the highest stop within the subtree so that subtrees ending before a query are skipped
+type intervalTree struct {
+	regions []Region
+	maxStop []int
+}
+
+func newIntervalTree(regions []Region) *intervalTree {
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Start < regions[j].Start
+	})
+	tree := &intervalTree{regions: regions, maxStop: make([]int, len(regions))}
+	tree.build(0, len(regions))
+	return tree
+}
+
+// build fills maxStop for the subtree of regions[lo:hi] and returns it
+func (tree *intervalTree) build(lo, hi int) int {
+	if lo >= hi {
+		return 0
+	}
+	mid := (lo + hi) / 2
+	maxStop := tree.regions[mid].Stop
+	if left := tree.build(lo, mid); left > maxStop {
+		maxStop = left
+	}
+	if right := tree.build(mid+1, hi); right > maxStop {
+		maxStop = right
+	}
+	tree.maxStop[mid] = maxStop
+	return maxStop
+}
+
+// overlaps reports whether a region of the subtree of regions[lo:hi] overlaps the span from start to stop
+func (tree *intervalTree) overlaps(lo, hi, start, stop int) bool {
+	if lo >= hi {
+		return false
+	}
+	mid := (lo + hi) / 2
+	if tree.maxStop[mid] < start {
+		return false
+	}
+	region := tree.regions[mid]
+	if region.Start <= stop && region.Stop >= start {
+		return true
+	}
+	if tree.overlaps(lo, mid, start, stop) {
+		return true
+	}
+	//Every region right of mid starts at or after it, so none can overlap a span that ends before it
+	return region.Start <= stop && tree.overlaps(mid+1, hi, start, stop)
+}
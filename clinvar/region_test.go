@@ -0,0 +1,76 @@
+package clinvar
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// overlapsBruteForce is the reference Overlaps is checked against
+func overlapsBruteForce(regions []Region, chr string, start, stop int) bool {
+	for _, region := range regions {
+		if normalizeChr(region.Chr) == normalizeChr(chr) && region.Start <= stop && region.Stop >= start {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRegionSetOverlapsMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	chrs := []string{"1", "chr1", "X", "chrM", "MT"}
+	for trial := 0; trial < 200; trial++ {
+		regions := []Region{}
+		for i := random.Intn(300); i > 0; i-- {
+			start := random.Intn(10000) + 1
+			region := Region{Chr: chrs[random.Intn(len(chrs))], Start: start, Stop: start + random.Intn(500)}
+			regions = append(regions, region)
+			switch random.Intn(4) {
+			case 0:
+				//Adjacent: starts right after the previous region stops
+				regions = append(regions, Region{Chr: region.Chr, Start: region.Stop + 1, Stop: region.Stop + 1 + random.Intn(50)})
+			case 1:
+				//Contained in the previous region
+				inner := region.Start + random.Intn(region.Stop-region.Start+1)
+				regions = append(regions, Region{Chr: region.Chr, Start: inner, Stop: inner + random.Intn(region.Stop-inner+1)})
+			}
+		}
+		set := NewRegionSet(append([]Region{}, regions...))
+
+		for query := 0; query < 500; query++ {
+			chr := chrs[random.Intn(len(chrs))]
+			start := random.Intn(11000) + 1
+			stop := start + random.Intn(50)
+			if got, want := set.Overlaps(chr, start, stop), overlapsBruteForce(regions, chr, start, stop); got != want {
+				t.Fatalf("trial %d: Overlaps(%s, %d, %d) = %v, want %v", trial, chr, start, stop, got, want)
+			}
+		}
+		//The positions around each region's ends are where off-by-one mistakes show
+		for _, region := range regions {
+			for _, position := range []int{region.Start - 1, region.Start, region.Stop, region.Stop + 1} {
+				if got, want := set.Overlaps(region.Chr, position, position), overlapsBruteForce(regions, region.Chr, position, position); got != want {
+					t.Fatalf("trial %d: Overlaps(%s, %d, %d) = %v, want %v", trial, region.Chr, position, position, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRegionSetChromosomeNames(t *testing.T) {
+	set := NewRegionSet([]Region{{Chr: "chr17", Start: 100, Stop: 200}, {Chr: "chrM", Start: 1, Stop: 10}})
+	for _, tc := range []struct {
+		chr         string
+		start, stop int
+		want        bool
+	}{
+		{"17", 150, 150, true},
+		{"chr17", 200, 300, true},
+		{"17", 201, 300, false},
+		{"17", 1, 99, false},
+		{"MT", 5, 5, true},
+		{"1", 150, 150, false},
+	} {
+		if got := set.Overlaps(tc.chr, tc.start, tc.stop); got != tc.want {
+			t.Errorf("Overlaps(%s, %d, %d) = %v, want %v", tc.chr, tc.start, tc.stop, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package clinvar decodes ClinVar variation release XML (ClinVarVariationRelease, schema 1.x and 2.x)
+// and flattens each VariationArchive into ClinVarVariationData.
+//
+// A Reader streams the VariationArchive elements of a release one at a time, and Extract flattens each of them:
+//
+//	reader, err := clinvar.NewReader(file)
+//	if err != nil {
+//		return err
+//	}
+//	for {
+//		variant, err := reader.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		variantInfo := clinvar.Extract(variant, "GRCh38")
+//		...
+//	}
+package clinvar
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Root element names used by ClinVar variation releases; the shipped files use ClinVarVariationRelease
+var releaseRootElements = []string{"ClinVarResult-Set", "ClinVarVariationRelease"}
+
+type ClinVarDataRelease struct {
+	XMLName                   xml.Name
+	Xsi                       string             `xml:"xsi,attr"`
+	NoNamespaceSchemaLocation string             `xml:"noNamespaceSchemaLocation,attr"`
+	ReleaseDate               string             `xml:"ReleaseDate,attr"`
+	Variants                  []VariationArchive `xml:"VariationArchive"`
+}
+
+type ClinVarDataReleaseInfo struct {
+	W3SchemaInfo         string
+	ClinVarSchemaVersion string
+	SchemaVersion        string
+	ClinVarReleaseDate   string
+}
+
+// ClinVarReleaseOutput stamps the extracted variants with the release they were loaded from
+type ClinVarReleaseOutput struct {
+	ReleaseInfo ClinVarDataReleaseInfo
+	Variants    []ClinVarVariationData
+}
+
+// Parse decodes a whole release into memory. Use a Reader for full-size releases
+func Parse(r io.Reader) (*ClinVarDataRelease, error) {
+	xmlDecoder := xml.NewDecoder(r)
+
+	//Parse XML file into the variant data struct variable (top level ClinVarDataRelease wrapper)
+	var data ClinVarDataRelease
+	if err := xmlDecoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	if !isReleaseRootElement(data.XMLName.Local) {
+		return nil, fmt.Errorf("unexpected root element %q", data.XMLName.Local)
+	}
+	if err := checkSchemaVersion(data.ReleaseInfo()); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func isReleaseRootElement(name string) bool {
+	for _, root := range releaseRootElements {
+		if name == root {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseInfo returns the schema and release date of the release
+func (data ClinVarDataRelease) ReleaseInfo() ClinVarDataReleaseInfo {
+	clinRelease := ClinVarDataReleaseInfo{}
+	clinRelease.W3SchemaInfo = data.Xsi
+	clinRelease.ClinVarSchemaVersion = data.NoNamespaceSchemaLocation
+	clinRelease.SchemaVersion = schemaVersion(data.NoNamespaceSchemaLocation)
+	clinRelease.ClinVarReleaseDate = data.ReleaseDate
+	return clinRelease
+}
+
+// extractReleaseInfoFromRoot reads the release attributes straight off the root start element when streaming
+func extractReleaseInfoFromRoot(root xml.StartElement) ClinVarDataReleaseInfo {
+	clinRelease := ClinVarDataReleaseInfo{}
+	for _, attr := range root.Attr {
+		switch attr.Name.Local {
+		case "xsi":
+			clinRelease.W3SchemaInfo = attr.Value
+		case "noNamespaceSchemaLocation":
+			clinRelease.ClinVarSchemaVersion = attr.Value
+			clinRelease.SchemaVersion = schemaVersion(attr.Value)
+		case "ReleaseDate":
+			clinRelease.ClinVarReleaseDate = attr.Value
+		}
+	}
+	return clinRelease
+}
+
+// ExtractAll extracts every variant of the release, see Extract
+func (data *ClinVarDataRelease) ExtractAll(assembly string) []ClinVarVariationData {
+	var allVariantsInfo []ClinVarVariationData
+	for _, variant := range data.Variants {
+		singleVariantInfo := variant.extractClinVarVariantData(assembly)
+		allVariantsInfo = append(allVariantsInfo, singleVariantInfo)
+	}
+	return allVariantsInfo
+}
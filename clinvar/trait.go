@@ -0,0 +1,162 @@
+package clinvar
+
+import "strings"
+
+// Trait is a condition of an aggregate classification, with its names, symbols, attributes,
+// citations and cross-references
+type Trait struct {
+	Text string `xml:",chardata"`
+	ID   string `xml:"ID,attr"`
+	Type string `xml:"Type,attr"`
+	Name []struct {
+		Text         string `xml:",chardata"`
+		ElementValue struct {
+			Text string `xml:",chardata"`
+			Type string `xml:"Type,attr"`
+		} `xml:"ElementValue"`
+		XRef []struct {
+			Text string `xml:",chardata"`
+			Type string `xml:"Type,attr"`
+			ID   string `xml:"ID,attr"`
+			DB   string `xml:"DB,attr"`
+		} `xml:"XRef"`
+	} `xml:"Name"`
+	XRef []struct {
+		Text string `xml:",chardata"`
+		ID   string `xml:"ID,attr"`
+		DB   string `xml:"DB,attr"`
+		Type string `xml:"Type,attr"`
+	} `xml:"XRef"`
+	Symbol []struct {
+		Text         string `xml:",chardata"`
+		ElementValue struct {
+			Text string `xml:",chardata"`
+			Type string `xml:"Type,attr"`
+		} `xml:"ElementValue"`
+		XRef []struct {
+			Text string `xml:",chardata"`
+			ID   string `xml:"ID,attr"`
+			DB   string `xml:"DB,attr"`
+			Type string `xml:"Type,attr"`
+		} `xml:"XRef"`
+	} `xml:"Symbol"`
+	AttributeSet []struct {
+		Text      string `xml:",chardata"`
+		Attribute struct {
+			Text         string `xml:",chardata"`
+			Type         string `xml:"Type,attr"`
+			IntegerValue string `xml:"integerValue,attr"`
+		} `xml:"Attribute"`
+		XRef []struct {
+			Text string `xml:",chardata"`
+			ID   string `xml:"ID,attr"`
+			DB   string `xml:"DB,attr"`
+			Type string `xml:"Type,attr"`
+		} `xml:"XRef"`
+	} `xml:"AttributeSet"`
+	Citation []struct {
+		Text   string `xml:",chardata"`
+		Type   string `xml:"Type,attr"`
+		Abbrev string `xml:"Abbrev,attr"`
+		ID     []struct {
+			Text   string `xml:",chardata"`
+			Source string `xml:"Source,attr"`
+		} `xml:"ID"`
+	} `xml:"Citation"`
+}
+
+// TraitAttribute is one AttributeSet of a trait, such as its mode of inheritance, age of onset or public definition
+type TraitAttribute struct {
+	Type         string
+	Value        string
+	IntegerValue int
+	XRefs        map[string][]XRef
+}
+
+// Trait attribute types, matched case-insensitively
+const (
+	modeOfInheritanceAttribute = "mode of inheritance"
+	ageOfOnsetAttribute        = "age of onset"
+	prevalenceAttribute        = "prevalence"
+	diseaseMechanismAttribute  = "disease mechanism"
+	publicDefinitionAttribute  = "public definition"
+	geneReviewsShortAttribute  = "GeneReviews short"
+)
+
+// extractTraitDetails adds the alternate names, symbols and attributes of the trait to nt,
+// pulling the attributes reports print alongside the condition into their own fields
+func (nt *Traits) extractTraitDetails(trait *Trait) {
+	nt.AlternateNames = []string{}
+	for _, name := range trait.Name {
+		if name.ElementValue.Type == "Alternate" {
+			nt.AlternateNames = append(nt.AlternateNames, name.ElementValue.Text)
+		}
+	}
+
+	nt.AlternateSymbols = []string{}
+	for _, symbol := range trait.Symbol {
+		if symbol.ElementValue.Type == "Preferred" {
+			nt.Symbol = symbol.ElementValue.Text
+		} else {
+			nt.AlternateSymbols = append(nt.AlternateSymbols, symbol.ElementValue.Text)
+		}
+	}
+
+	nt.Attributes = []TraitAttribute{}
+	nt.ModeOfInheritance = []string{}
+	nt.AgeOfOnset = []string{}
+	nt.Prevalence = []string{}
+	nt.DiseaseMechanism = []string{}
+	nt.GeneReviews = []string{}
+	for _, attributeSet := range trait.AttributeSet {
+		na := TraitAttribute{
+			Type:         attributeSet.Attribute.Type,
+			Value:        attributeSet.Attribute.Text,
+			IntegerValue: parseCount(attributeSet.Attribute.IntegerValue),
+			XRefs:        make(map[string][]XRef)}
+		for _, xref := range attributeSet.XRef {
+			na.XRefs[xref.DB] = append(na.XRefs[xref.DB], XRef{ID: xref.ID, Type: xref.Type})
+			if xref.DB == "GeneReviews" {
+				nt.addGeneReviews(xref.ID)
+			}
+		}
+		nt.Attributes = append(nt.Attributes, na)
+
+		switch {
+		case strings.EqualFold(na.Type, modeOfInheritanceAttribute):
+			nt.ModeOfInheritance = append(nt.ModeOfInheritance, na.Value)
+		case strings.EqualFold(na.Type, ageOfOnsetAttribute):
+			nt.AgeOfOnset = append(nt.AgeOfOnset, na.Value)
+		case strings.EqualFold(na.Type, prevalenceAttribute):
+			nt.Prevalence = append(nt.Prevalence, na.Value)
+		case strings.EqualFold(na.Type, diseaseMechanismAttribute):
+			nt.DiseaseMechanism = append(nt.DiseaseMechanism, na.Value)
+		case strings.EqualFold(na.Type, publicDefinitionAttribute):
+			nt.PublicDefinition = na.Value
+		case strings.EqualFold(na.Type, geneReviewsShortAttribute):
+			nt.GeneReviewsShort = na.Value
+		}
+	}
+
+	//GeneReviews chapters are also cited by their BookShelf ID
+	for _, citation := range trait.Citation {
+		if citation.Abbrev != "GeneReviews" {
+			continue
+		}
+		for _, id := range citation.ID {
+			if id.Source == "BookShelf" {
+				nt.addGeneReviews(id.Text)
+			}
+		}
+	}
+}
+
+// addGeneReviews records a GeneReviews chapter (NBK ID) of the trait once
+func (nt *Traits) addGeneReviews(id string) {
+	for _, existing := range nt.GeneReviews {
+		if existing == id {
+			return
+		}
+	}
+	nt.GeneReviews = append(nt.GeneReviews, id)
+}
@@ -0,0 +1,362 @@
+package clinvar
+
+type ClinVarVariationData struct {
+	Accesssion                 string
+	Version                    string
+	Type                       string
+	GeneAffected               string
+	GeneEntrezID               string
+	GeneOmimID                 string
+	NcbiRefSeq                 string
+	LocationType               string
+	DbSNPID                    string
+	GenomeVersion              string
+	ChromLocation              string
+	ChromStart                 string
+	ChromStop                  string
+	Length                     string
+	OmimID                     string
+	ReviewStatus               string
+	Interpretation             string
+	DateLastEvaluated          string
+	ClassificationHistory      []ClassificationHistory
+	HGVData                    []HGVData
+	HGVSExpressions            []HGVSExpression
+	MANESelectCoding           string
+	MANESelectProtein          string
+	AlleleFrequencies          []AlleleFrequency
+	GlobalMinorAlleleFrequency *GlobalMinorAlleleFrequency
+	MaxAlleleFrequency         float64
+	XRefs                      map[string][]XRef
+	RCVData                    []RCVData
+	ClinicalInterpretations    ClinicalInterpretations
+	Locations                  []VariantLocation
+	Genes                      []Gene
+	SomaticClinicalImpact      *SomaticClinicalImpact
+	Oncogenicity               *Oncogenicity
+	ClinicalAssertions         []ClinicalAssertions
+	ObservationSummary         ObservationSummary
+	RecordType                 string
+	MemberAlleles              []MemberAllele
+	IncludedIn                 []string
+}
+
+// VariantLocation is the variant's SequenceLocation on one assembly, including the
+// VCF-normalized position and alleles ClinVar provides for it. Coordinates are 1-based; 0 means not provided
+type VariantLocation struct {
+	Assembly           string
+	Chr                string
+	Accession          string
+	Start              int
+	Stop               int
+	DisplayStart       int
+	DisplayStop        int
+	Length             int
+	PositionVCF        int
+	ReferenceAlleleVCF string
+	AlternateAlleleVCF string
+}
+
+type HGVData struct {
+	Consequence string
+}
+
+type RCVData struct {
+	AccessionID     string
+	Version         string
+	Interpretation  string
+	Condition       string
+	SubmissionCount string
+	ReviewStatus    string
+	MedGenID        string
+	TraitSetID      string
+}
+
+type ClinicalInterpretations struct {
+	Citations []Citations
+	Trait     []Traits
+}
+
+type Citations struct {
+	CitationSource string
+	CitationID     string
+	URL            string
+}
+
+type Traits struct {
+	ID                string
+	Name              string
+	AlternateNames    []string
+	Symbol            string
+	AlternateSymbols  []string
+	Citations         []Citations
+	PhenotypicSeries  string
+	MIM               string
+	MedGen            string
+	Orph              string
+	ModeOfInheritance []string
+	AgeOfOnset        []string
+	Prevalence        []string
+	DiseaseMechanism  []string
+	PublicDefinition  string
+	GeneReviewsShort  string
+	GeneReviews       []string
+	Attributes        []TraitAttribute
+}
+
+// ClinicalAssertions is one submitted clinical assertion (SCV): who submitted it and what they said
+type ClinicalAssertions struct {
+	ID                    string
+	Accession             string
+	Version               string
+	SubmitterName         string
+	OrgID                 string
+	OrganizationCategory  string
+	OrgAbbreviation       string
+	SubmissionDate        string
+	DateLastUpdated       string
+	RecordStatus          string
+	ReviewStatus          string
+	Interpretation        string
+	DateLastEvaluated     string
+	SomaticClinicalImpact *SomaticClinicalImpactTier
+	Oncogenicity          string
+	AssertionMethod       string
+	ModeOfInheritance     string
+	Comments              []string
+	Citations             []Citations
+	Conditions            []AssertionConditions
+	Observations          []Observation
+}
+
+// AssertionConditions is a condition as the submitter described it, normalized to a MedGen concept through TraitMappingList
+type AssertionConditions struct {
+	TraitType     string
+	SubmittedName string
+	SubmittedXRef string
+	MedGenCUI     string
+	MedGenName    string
+}
+
+// Extract flattens a VariationArchive into the ClinVarVariationData written out by the parser. The location
+// on the given assembly (or the first location when the variant has none on it) populates the legacy flat location fields
+func Extract(variant *VariationArchive, assembly string) ClinVarVariationData {
+	return variant.extractClinVarVariantData(assembly)
+}
+
+// extractClinVarVariantData implements Extract
+func (variant *VariationArchive) extractClinVarVariantData(assembly string) ClinVarVariationData {
+
+	singleVariantInfo := ClinVarVariationData{}
+	allele := variant.simpleAllele()
+	record := variant.record()
+	germline := record.germlineClassification()
+
+	singleVariantInfo.Accesssion = variant.Accession
+	singleVariantInfo.Version = variant.Version
+	singleVariantInfo.Type = variant.VariationType
+
+	if len(allele.GeneList.Gene) > 0 {
+		singleVariantInfo.GeneAffected = allele.GeneList.Gene[0].Symbol
+		singleVariantInfo.GeneEntrezID = allele.GeneList.Gene[0].GeneID
+		singleVariantInfo.GeneOmimID = allele.GeneList.Gene[0].OMIM
+		//TODO: spew.Dump()
+	} else {
+		singleVariantInfo.GeneAffected = "notProvided"
+		singleVariantInfo.GeneEntrezID = "notProvided"
+		singleVariantInfo.GeneOmimID = "notProvided"
+	}
+
+	singleVariantInfo.NcbiRefSeq = allele.CanonicalSPDI
+
+	singleVariantInfo.XRefs = variant.extractXRefs()
+	singleVariantInfo.DbSNPID = singleVariantInfo.firstXRefID("dbSNP")
+
+	singleVariantInfo.ChromLocation = allele.Location.CytogeneticLocation
+
+	variantLocations := allele.extractLocations()
+	singleVariantInfo.Locations = variantLocations
+
+	singleVariantInfo.Genes = variant.extractGenes(variantLocations)
+
+	if location, ok := singleVariantInfo.locationForAssembly(assembly); ok {
+		singleVariantInfo.GenomeVersion = location.Assembly
+		singleVariantInfo.ChromStart = coordinateString(location.Start)
+		singleVariantInfo.ChromStop = coordinateString(location.Stop)
+		singleVariantInfo.Length = coordinateString(location.Length)
+		singleVariantInfo.LocationType = summarizeLocationType(singleVariantInfo.Genes, location.Assembly)
+	} else {
+		singleVariantInfo.GenomeVersion = "notProvided"
+		singleVariantInfo.ChromStart = "notProvided"
+		singleVariantInfo.ChromStop = "notProvided"
+		singleVariantInfo.Length = "notProvided"
+		singleVariantInfo.LocationType = "notProvided"
+	}
+
+	singleVariantInfo.OmimID = singleVariantInfo.firstXRefID("OMIM")
+
+	singleVariantInfo.Interpretation = germline.Description
+	singleVariantInfo.DateLastEvaluated = germline.DateLastEvaluated
+	singleVariantInfo.ClassificationHistory = variant.extractClassificationHistory()
+	singleVariantInfo.SomaticClinicalImpact = variant.extractSomaticClinicalImpact()
+	singleVariantInfo.Oncogenicity = variant.extractOncogenicity()
+
+	//Schema 2.x records carry the review status on each classification instead of the record:
+	//the germline one, else the oncogenicity one, else the somatic clinical impact one
+	singleVariantInfo.ReviewStatus = record.ReviewStatus
+	if singleVariantInfo.ReviewStatus == "" {
+		singleVariantInfo.ReviewStatus = germline.ReviewStatus
+	}
+	if singleVariantInfo.ReviewStatus == "" && singleVariantInfo.Oncogenicity != nil {
+		singleVariantInfo.ReviewStatus = singleVariantInfo.Oncogenicity.ReviewStatus
+	}
+	if singleVariantInfo.ReviewStatus == "" && singleVariantInfo.SomaticClinicalImpact != nil {
+		singleVariantInfo.ReviewStatus = singleVariantInfo.SomaticClinicalImpact.ReviewStatus
+	}
+	if variant.IncludedRecord != nil && singleVariantInfo.ReviewStatus == "" {
+		singleVariantInfo.ReviewStatus = variant.IncludedRecord.ReviewStatus
+	}
+
+	variantHgvConsequence := []HGVData{}
+	for _, hgvs := range allele.HGVSlist.HGVS {
+		for _, consequence := range hgvs.MolecularConsequence {
+			nc := consequence.Type
+			if nc == "" {
+				continue
+			}
+			skip := false
+			for _, cons := range variantHgvConsequence {
+				oc := cons.Consequence
+				if nc == oc {
+					skip = true
+					break
+				}
+			}
+			if !skip {
+				variantHgvConsequence = append(variantHgvConsequence, HGVData{
+					Consequence: nc})
+			}
+		}
+	}
+	singleVariantInfo.HGVData = variantHgvConsequence
+
+	singleVariantInfo.AlleleFrequencies, singleVariantInfo.MaxAlleleFrequency = variant.extractAlleleFrequencies()
+	singleVariantInfo.GlobalMinorAlleleFrequency = variant.extractGlobalMinorAlleleFrequency()
+
+	singleVariantInfo.HGVSExpressions = variant.extractHGVSExpressions()
+	for _, hgvs := range singleVariantInfo.HGVSExpressions {
+		if hgvs.MANESelect {
+			singleVariantInfo.MANESelectCoding = hgvs.NucleotideExpression
+			singleVariantInfo.MANESelectProtein = hgvs.ProteinExpression
+			break
+		}
+	}
+
+	variantAllRcvs := []RCVData{}
+	for _, rcvs := range record.RCVList.RCVAccession {
+		condition := rcvs.InterpretedConditionList.InterpretedCondition
+		traitSetID := rcvs.InterpretedConditionList.TraitSetID
+		interpretation, reviewStatus, submissionCount := rcvs.Interpretation, rcvs.ReviewStatus, rcvs.SubmissionCount
+		//Schema 2.x moves the RCV's condition and germline classification into child elements
+		if rcvs.ClassifiedConditionList.TraitSetID != "" {
+			condition = rcvs.ClassifiedConditionList.ClassifiedCondition
+			traitSetID = rcvs.ClassifiedConditionList.TraitSetID
+		}
+		if classification := rcvs.RCVClassifications.GermlineClassification; classification != nil {
+			interpretation = classification.Description.Text
+			reviewStatus = classification.ReviewStatus
+			submissionCount = classification.Description.SubmissionCount
+		}
+		if condition.DB == "MedGen" {
+			variantAllRcvs = append(variantAllRcvs, RCVData{
+				AccessionID:     rcvs.Accession,
+				Version:         rcvs.Version,
+				Interpretation:  interpretation,
+				Condition:       condition.Text,
+				SubmissionCount: submissionCount,
+				ReviewStatus:    reviewStatus,
+				MedGenID:        condition.ID,
+				TraitSetID:      traitSetID})
+
+		} else {
+			variantAllRcvs = append(variantAllRcvs, RCVData{
+				AccessionID:     rcvs.Accession,
+				Version:         rcvs.Version,
+				Interpretation:  interpretation,
+				SubmissionCount: submissionCount,
+				ReviewStatus:    reviewStatus,
+				MedGenID:        "notProvided",
+				Condition:       "notProvided",
+				TraitSetID:      traitSetID})
+		}
+
+	}
+	singleVariantInfo.RCVData = variantAllRcvs
+
+	variantAllCitations := []Citations{}
+	for _, citations := range germline.Citation {
+		variantAllCitations = append(variantAllCitations, Citations{
+			CitationSource: citations.ID.Source,
+			CitationID:     citations.ID.Text,
+			URL:            citations.URL})
+	}
+	singleVariantInfo.ClinicalInterpretations.Citations = variantAllCitations
+
+	variantAllTraits := []Traits{}
+	for _, trait := range germline.ConditionList.TraitSet {
+		for _, name := range trait.Trait.Name {
+			nt := Traits{ID: trait.Trait.ID}
+
+			if name.ElementValue.Type == "Preferred" {
+				nt.Name = name.ElementValue.Text
+			} else {
+				continue
+			}
+			for _, xRefTrait := range trait.Trait.XRef {
+				if xRefTrait.DB == "Orphanet" {
+					nt.Orph = xRefTrait.ID
+				} else if xRefTrait.DB == "MedGen" {
+					nt.MedGen = xRefTrait.ID
+				} else if xRefTrait.DB == "OMIM" {
+					if xRefTrait.Type == "Phenotypic series" {
+						nt.PhenotypicSeries = xRefTrait.ID
+					} else if xRefTrait.Type == "MIM" {
+						nt.MIM = xRefTrait.ID
+					}
+				}
+			}
+			for _, citations := range trait.Trait.Citation {
+				for _, citationsInfo := range citations.ID {
+					nt.Citations = append(nt.Citations, Citations{
+						CitationSource: citationsInfo.Source,
+						CitationID:     citationsInfo.Text})
+				}
+			}
+			nt.extractTraitDetails(&trait.Trait)
+			variantAllTraits = append(variantAllTraits, nt)
+		}
+	}
+	singleVariantInfo.ClinicalInterpretations.Trait = variantAllTraits
+
+	singleVariantInfo.ClinicalAssertions = variant.extractClinicalAssertions()
+	singleVariantInfo.ObservationSummary = summarizeObservations(singleVariantInfo.ClinicalAssertions)
+
+	singleVariantInfo.RecordType = variant.RecordType
+	singleVariantInfo.MemberAlleles = variant.extractMemberAlleles()
+	singleVariantInfo.IncludedIn = variant.extractIncludedIn()
+
+	return singleVariantInfo
+}
+
+// locationForAssembly returns the variant location on the given assembly, falling back to the first location
+func (variantInfo *ClinVarVariationData) locationForAssembly(assembly string) (VariantLocation, bool) {
+	for _, location := range variantInfo.Locations {
+		if location.Assembly == assembly {
+			return location, true
+		}
+	}
+	if len(variantInfo.Locations) > 0 {
+		return variantInfo.Locations[0], true
+	}
+	return VariantLocation{}, false
+}
@@ -0,0 +1,435 @@
+package clinvar
+
+type VariationArchive struct {
+	Text                string             `xml:",chardata"`
+	VariationID         string             `xml:"VariationID,attr"`
+	VariationName       string             `xml:"VariationName,attr"`
+	VariationType       string             `xml:"VariationType,attr"`
+	DateCreated         string             `xml:"DateCreated,attr"`
+	DateLastUpdated     string             `xml:"DateLastUpdated,attr"`
+	Accession           string             `xml:"Accession,attr"`
+	Version             string             `xml:"Version,attr"`
+	RecordType          string             `xml:"RecordType,attr"`
+	NumberOfSubmissions string             `xml:"NumberOfSubmissions,attr"`
+	NumberOfSubmitters  string             `xml:"NumberOfSubmitters,attr"`
+	RecordStatus        string             `xml:"RecordStatus"`
+	Species             string             `xml:"Species"`
+	InterpretedRecord   InterpretedRecord  `xml:"InterpretedRecord"`
+	ClassifiedRecord    *InterpretedRecord `xml:"ClassifiedRecord"`
+	IncludedRecord      *IncludedRecord    `xml:"IncludedRecord"`
+}
+
+// InterpretedRecord holds the variant and its interpretations. Schema 2.x files name it ClassifiedRecord
+// and replace Interpretations with Classifications; both layouts decode into this struct
+type InterpretedRecord struct {
+	Text         string       `xml:",chardata"`
+	SimpleAllele SimpleAllele `xml:"SimpleAllele"`
+	Haplotype    *Haplotype   `xml:"Haplotype"`
+	Genotype     *Genotype    `xml:"Genotype"`
+	ReviewStatus string       `xml:"ReviewStatus"`
+	RCVList      struct {
+		Text         string `xml:",chardata"`
+		RCVAccession []struct {
+			Text                     string `xml:",chardata"`
+			Title                    string `xml:"Title,attr"`
+			DateLastEvaluated        string `xml:"DateLastEvaluated,attr"`
+			ReviewStatus             string `xml:"ReviewStatus,attr"`
+			Interpretation           string `xml:"Interpretation,attr"`
+			SubmissionCount          string `xml:"SubmissionCount,attr"`
+			Accession                string `xml:"Accession,attr"`
+			Version                  string `xml:"Version,attr"`
+			InterpretedConditionList struct {
+				Text                 string `xml:",chardata"`
+				TraitSetID           string `xml:"TraitSetID,attr"`
+				InterpretedCondition struct {
+					Text string `xml:",chardata"`
+					DB   string `xml:"DB,attr"`
+					ID   string `xml:"ID,attr"`
+				} `xml:"InterpretedCondition"`
+			} `xml:"InterpretedConditionList"`
+			ClassifiedConditionList struct {
+				Text                string `xml:",chardata"`
+				TraitSetID          string `xml:"TraitSetID,attr"`
+				ClassifiedCondition struct {
+					Text string `xml:",chardata"`
+					DB   string `xml:"DB,attr"`
+					ID   string `xml:"ID,attr"`
+				} `xml:"ClassifiedCondition"`
+			} `xml:"ClassifiedConditionList"`
+			RCVClassifications struct {
+				Text                   string `xml:",chardata"`
+				GermlineClassification *struct {
+					Text         string `xml:",chardata"`
+					ReviewStatus string `xml:"ReviewStatus"`
+					Description  struct {
+						Text              string `xml:",chardata"`
+						DateLastEvaluated string `xml:"DateLastEvaluated,attr"`
+						SubmissionCount   string `xml:"SubmissionCount,attr"`
+					} `xml:"Description"`
+				} `xml:"GermlineClassification"`
+			} `xml:"RCVClassifications"`
+		} `xml:"RCVAccession"`
+	} `xml:"RCVList"`
+	Interpretations struct {
+		Text           string                  `xml:",chardata"`
+		Interpretation AggregateClassification `xml:"Interpretation"`
+	} `xml:"Interpretations"`
+	Classifications       Classifications `xml:"Classifications"`
+	ClinicalAssertionList struct {
+		Text              string `xml:",chardata"`
+		ClinicalAssertion []struct {
+			Text                  string `xml:",chardata"`
+			ID                    string `xml:"ID,attr"`
+			DateCreated           string `xml:"DateCreated,attr"`
+			DateLastUpdated       string `xml:"DateLastUpdated,attr"`
+			SubmissionDate        string `xml:"SubmissionDate,attr"`
+			FDARecognizedDatabase string `xml:"FDARecognizedDatabase,attr"`
+			ClinVarSubmissionID   struct {
+				Text                string `xml:",chardata"`
+				LocalKey            string `xml:"localKey,attr"`
+				Title               string `xml:"title,attr"`
+				LocalKeyIsSubmitted string `xml:"localKeyIsSubmitted,attr"`
+				SubmittedAssembly   string `xml:"submittedAssembly,attr"`
+			} `xml:"ClinVarSubmissionID"`
+			ClinVarAccession struct {
+				Text                 string `xml:",chardata"`
+				Accession            string `xml:"Accession,attr"`
+				Type                 string `xml:"Type,attr"`
+				Version              string `xml:"Version,attr"`
+				SubmitterName        string `xml:"SubmitterName,attr"`
+				OrgID                string `xml:"OrgID,attr"`
+				OrganizationCategory string `xml:"OrganizationCategory,attr"`
+				OrgAbbreviation      string `xml:"OrgAbbreviation,attr"`
+			} `xml:"ClinVarAccession"`
+			RecordStatus   string `xml:"RecordStatus"`
+			ReviewStatus   string `xml:"ReviewStatus"`
+			Interpretation struct {
+				Text              string `xml:",chardata"`
+				DateLastEvaluated string `xml:"DateLastEvaluated,attr"`
+				Description       string `xml:"Description"`
+				Citation          []struct {
+					Text string `xml:",chardata"`
+					ID   struct {
+						Text   string `xml:",chardata"`
+						Source string `xml:"Source,attr"`
+					} `xml:"ID"`
+					URL string `xml:"URL"`
+				} `xml:"Citation"`
+				Comment []struct {
+					Text string `xml:",chardata"`
+					Type string `xml:"Type,attr"`
+				} `xml:"Comment"`
+			} `xml:"Interpretation"`
+			Classification struct {
+				Text                   string `xml:",chardata"`
+				DateLastEvaluated      string `xml:"DateLastEvaluated,attr"`
+				ReviewStatus           string `xml:"ReviewStatus"`
+				GermlineClassification string `xml:"GermlineClassification"`
+				SomaticClinicalImpact  *struct {
+					Text                               string `xml:",chardata"`
+					ClinicalImpactAssertionType        string `xml:"ClinicalImpactAssertionType,attr"`
+					ClinicalImpactClinicalSignificance string `xml:"ClinicalImpactClinicalSignificance,attr"`
+				} `xml:"SomaticClinicalImpact"`
+				OncogenicityClassification string `xml:"OncogenicityClassification"`
+				Citation                   []struct {
+					Text string `xml:",chardata"`
+					ID   struct {
+						Text   string `xml:",chardata"`
+						Source string `xml:"Source,attr"`
+					} `xml:"ID"`
+					URL string `xml:"URL"`
+				} `xml:"Citation"`
+				Comment []struct {
+					Text string `xml:",chardata"`
+					Type string `xml:"Type,attr"`
+				} `xml:"Comment"`
+			} `xml:"Classification"`
+			Assertion      string `xml:"Assertion"`
+			ObservedInList struct {
+				Text       string `xml:",chardata"`
+				ObservedIn []struct {
+					Text   string `xml:",chardata"`
+					Sample struct {
+						Text    string `xml:",chardata"`
+						Origin  string `xml:"Origin"`
+						Species struct {
+							Text       string `xml:",chardata"`
+							TaxonomyId string `xml:"TaxonomyId,attr"`
+						} `xml:"Species"`
+						AffectedStatus string `xml:"AffectedStatus"`
+						NumberTested   string `xml:"NumberTested"`
+					} `xml:"Sample"`
+					Method struct {
+						Text         string `xml:",chardata"`
+						MethodType   string `xml:"MethodType"`
+						TypePlatform string `xml:"TypePlatform"`
+					} `xml:"Method"`
+					ObservedData []struct {
+						Text      string `xml:",chardata"`
+						Attribute struct {
+							Text         string `xml:",chardata"`
+							Type         string `xml:"Type,attr"`
+							IntegerValue string `xml:"integerValue,attr"`
+						} `xml:"Attribute"`
+						Citation []struct {
+							Text string `xml:",chardata"`
+							ID   struct {
+								Text   string `xml:",chardata"`
+								Source string `xml:"Source,attr"`
+							} `xml:"ID"`
+						} `xml:"Citation"`
+						XRef struct {
+							Text string `xml:",chardata"`
+							DB   string `xml:"DB,attr"`
+							ID   string `xml:"ID,attr"`
+							Type string `xml:"Type,attr"`
+						} `xml:"XRef"`
+					} `xml:"ObservedData"`
+				} `xml:"ObservedIn"`
+			} `xml:"ObservedInList"`
+			SimpleAllele struct {
+				Text     string `xml:",chardata"`
+				GeneList struct {
+					Text string `xml:",chardata"`
+					Gene struct {
+						Text   string `xml:",chardata"`
+						Symbol string `xml:"Symbol,attr"`
+					} `xml:"Gene"`
+				} `xml:"GeneList"`
+				Name          string `xml:"Name"`
+				Type          string `xml:"Type"`
+				OtherNameList struct {
+					Text string `xml:",chardata"`
+					Name struct {
+						Text string `xml:",chardata"`
+						Type string `xml:"Type,attr"`
+					} `xml:"Name"`
+				} `xml:"OtherNameList"`
+				XRefList struct {
+					Text string `xml:",chardata"`
+					XRef struct {
+						Text string `xml:",chardata"`
+						DB   string `xml:"DB,attr"`
+						ID   string `xml:"ID,attr"`
+						Type string `xml:"Type,attr"`
+					} `xml:"XRef"`
+				} `xml:"XRefList"`
+				AttributeSet struct {
+					Text      string `xml:",chardata"`
+					Attribute struct {
+						Text string `xml:",chardata"`
+						Type string `xml:"Type,attr"`
+					} `xml:"Attribute"`
+				} `xml:"AttributeSet"`
+				Location struct {
+					Text             string `xml:",chardata"`
+					SequenceLocation struct {
+						Text            string `xml:",chardata"`
+						Assembly        string `xml:"Assembly,attr"`
+						Chr             string `xml:"Chr,attr"`
+						AlternateAllele string `xml:"alternateAllele,attr"`
+						ReferenceAllele string `xml:"referenceAllele,attr"`
+						Start           string `xml:"start,attr"`
+						Stop            string `xml:"stop,attr"`
+						Length          string `xml:"Length,attr"`
+					} `xml:"SequenceLocation"`
+				} `xml:"Location"`
+			} `xml:"SimpleAllele"`
+			TraitSet struct {
+				Text  string `xml:",chardata"`
+				Type  string `xml:"Type,attr"`
+				Trait []struct {
+					Text string `xml:",chardata"`
+					Type string `xml:"Type,attr"`
+					Name []struct {
+						Text         string `xml:",chardata"`
+						ElementValue struct {
+							Text string `xml:",chardata"`
+							Type string `xml:"Type,attr"`
+						} `xml:"ElementValue"`
+					} `xml:"Name"`
+					XRef []struct {
+						Text string `xml:",chardata"`
+						DB   string `xml:"DB,attr"`
+						ID   string `xml:"ID,attr"`
+						Type string `xml:"Type,attr"`
+					} `xml:"XRef"`
+				} `xml:"Trait"`
+			} `xml:"TraitSet"`
+			AttributeSet []struct {
+				Text      string `xml:",chardata"`
+				Attribute struct {
+					Text string `xml:",chardata"`
+					Type string `xml:"Type,attr"`
+				} `xml:"Attribute"`
+				Citation struct {
+					Text string `xml:",chardata"`
+					URL  string `xml:"URL"`
+					ID   struct {
+						Text   string `xml:",chardata"`
+						Source string `xml:"Source,attr"`
+					} `xml:"ID"`
+				} `xml:"Citation"`
+			} `xml:"AttributeSet"`
+			SubmissionNameList struct {
+				Text           string `xml:",chardata"`
+				SubmissionName string `xml:"SubmissionName"`
+			} `xml:"SubmissionNameList"`
+			Comment []struct {
+				Text string `xml:",chardata"`
+				Type string `xml:"Type,attr"`
+			} `xml:"Comment"`
+		} `xml:"ClinicalAssertion"`
+	} `xml:"ClinicalAssertionList"`
+	TraitMappingList struct {
+		Text         string `xml:",chardata"`
+		TraitMapping []struct {
+			Text                string `xml:",chardata"`
+			ClinicalAssertionID string `xml:"ClinicalAssertionID,attr"`
+			TraitType           string `xml:"TraitType,attr"`
+			MappingType         string `xml:"MappingType,attr"`
+			MappingValue        string `xml:"MappingValue,attr"`
+			MappingRef          string `xml:"MappingRef,attr"`
+			MedGen              struct {
+				Text string `xml:",chardata"`
+				CUI  string `xml:"CUI,attr"`
+				Name string `xml:"Name,attr"`
+			} `xml:"MedGen"`
+		} `xml:"TraitMapping"`
+	} `xml:"TraitMappingList"`
+}
+
+// SimpleAllele is a single variant allele. It is the interpreted allele of most VariationArchives
+// and the member alleles of Haplotype and Genotype records
+type SimpleAllele struct {
+	Text        string `xml:",chardata"`
+	AlleleID    string `xml:"AlleleID,attr"`
+	VariationID string `xml:"VariationID,attr"`
+	GeneList    struct {
+		Text string `xml:",chardata"`
+		Gene []struct {
+			Text             string `xml:",chardata"`
+			Symbol           string `xml:"Symbol,attr"`
+			FullName         string `xml:"FullName,attr"`
+			GeneID           string `xml:"GeneID,attr"`
+			HGNCID           string `xml:"HGNC_ID,attr"`
+			Source           string `xml:"Source,attr"`
+			RelationshipType string `xml:"RelationshipType,attr"`
+			Location         struct {
+				Text                string `xml:",chardata"`
+				CytogeneticLocation string `xml:"CytogeneticLocation"`
+				SequenceLocation    []struct {
+					Text                     string `xml:",chardata"`
+					Assembly                 string `xml:"Assembly,attr"`
+					AssemblyAccessionVersion string `xml:"AssemblyAccessionVersion,attr"`
+					AssemblyStatus           string `xml:"AssemblyStatus,attr"`
+					Chr                      string `xml:"Chr,attr"`
+					Accession                string `xml:"Accession,attr"`
+					Start                    string `xml:"start,attr"`
+					Stop                     string `xml:"stop,attr"`
+					DisplayStart             string `xml:"display_start,attr"`
+					DisplayStop              string `xml:"display_stop,attr"`
+					Strand                   string `xml:"Strand,attr"`
+				} `xml:"SequenceLocation"`
+			} `xml:"Location"`
+			OMIM               string `xml:"OMIM"`
+			Haploinsufficiency struct {
+				Text          string `xml:",chardata"`
+				LastEvaluated string `xml:"last_evaluated,attr"`
+				ClinGen       string `xml:"ClinGen,attr"`
+			} `xml:"Haploinsufficiency"`
+			Triplosensitivity struct {
+				Text          string `xml:",chardata"`
+				LastEvaluated string `xml:"last_evaluated,attr"`
+				ClinGen       string `xml:"ClinGen,attr"`
+			} `xml:"Triplosensitivity"`
+			Property []string `xml:"Property"`
+		} `xml:"Gene"`
+	} `xml:"GeneList"`
+	Name        string `xml:"Name"`
+	Type        string `xml:"Type"`
+	VariantType string `xml:"VariantType"`
+	Location    struct {
+		Text                string `xml:",chardata"`
+		CytogeneticLocation string `xml:"CytogeneticLocation"`
+		SequenceLocation    []struct {
+			Text                     string `xml:",chardata"`
+			Assembly                 string `xml:"Assembly,attr"`
+			AssemblyAccessionVersion string `xml:"AssemblyAccessionVersion,attr"`
+			ForDisplay               string `xml:"forDisplay,attr"`
+			AssemblyStatus           string `xml:"AssemblyStatus,attr"`
+			Chr                      string `xml:"Chr,attr"`
+			Accession                string `xml:"Accession,attr"`
+			Start                    string `xml:"start,attr"`
+			Stop                     string `xml:"stop,attr"`
+			DisplayStart             string `xml:"display_start,attr"`
+			DisplayStop              string `xml:"display_stop,attr"`
+			Length                   string `xml:"Length,attr"`
+			VariantLength            string `xml:"variantLength,attr"`
+			PositionVCF              string `xml:"positionVCF,attr"`
+			ReferenceAlleleVCF       string `xml:"referenceAlleleVCF,attr"`
+			AlternateAlleleVCF       string `xml:"alternateAlleleVCF,attr"`
+		} `xml:"SequenceLocation"`
+	} `xml:"Location"`
+	OtherNameList struct {
+		Text string   `xml:",chardata"`
+		Name []string `xml:"Name"`
+	} `xml:"OtherNameList"`
+	XRefList struct {
+		Text string `xml:",chardata"`
+		XRef []struct {
+			Text string `xml:",chardata"`
+			Type string `xml:"Type,attr"`
+			ID   string `xml:"ID,attr"`
+			DB   string `xml:"DB,attr"`
+		} `xml:"XRef"`
+	} `xml:"XRefList"`
+	CanonicalSPDI string `xml:"CanonicalSPDI"`
+	HGVSlist      struct {
+		Text string `xml:",chardata"`
+		HGVS []struct {
+			Text                 string `xml:",chardata"`
+			Type                 string `xml:"Type,attr"`
+			Assembly             string `xml:"Assembly,attr"`
+			NucleotideExpression struct {
+				Text                     string `xml:",chardata"`
+				SequenceAccessionVersion string `xml:"sequenceAccessionVersion,attr"`
+				SequenceAccession        string `xml:"sequenceAccession,attr"`
+				SequenceVersion          string `xml:"sequenceVersion,attr"`
+				Change                   string `xml:"change,attr"`
+				Assembly                 string `xml:"Assembly,attr"`
+				MANESelect               string `xml:"MANESelect,attr"`
+				Expression               string `xml:"Expression"`
+			} `xml:"NucleotideExpression"`
+			MolecularConsequence []struct {
+				Text string `xml:",chardata"`
+				ID   string `xml:"ID,attr"`
+				Type string `xml:"Type,attr"`
+				DB   string `xml:"DB,attr"`
+			} `xml:"MolecularConsequence"`
+			ProteinExpression struct {
+				Text                     string `xml:",chardata"`
+				SequenceAccessionVersion string `xml:"sequenceAccessionVersion,attr"`
+				SequenceAccession        string `xml:"sequenceAccession,attr"`
+				SequenceVersion          string `xml:"sequenceVersion,attr"`
+				Change                   string `xml:"change,attr"`
+				Expression               string `xml:"Expression"`
+			} `xml:"ProteinExpression"`
+		} `xml:"HGVS"`
+	} `xml:"HGVSlist"`
+	AlleleFrequencyList struct {
+		Text            string `xml:",chardata"`
+		AlleleFrequency []struct {
+			Text   string `xml:",chardata"`
+			Value  string `xml:"Value,attr"`
+			Source string `xml:"Source,attr"`
+		} `xml:"AlleleFrequency"`
+	} `xml:"AlleleFrequencyList"`
+	GlobalMinorAlleleFrequency struct {
+		Text        string `xml:",chardata"`
+		Value       string `xml:"Value,attr"`
+		Source      string `xml:"Source,attr"`
+		MinorAllele string `xml:"MinorAllele,attr"`
+	} `xml:"GlobalMinorAlleleFrequency"`
+	ProteinChange []string `xml:"ProteinChange"`
+}
@@ -0,0 +1,24 @@
+package clinvar
+
+// XRef is one cross-reference of the variant; XRefs are grouped by their DB (dbSNP, OMIM, ClinGen, UniProtKB, dbVar, ...)
+type XRef struct {
+	ID   string
+	Type string
+}
+
+// extractXRefs keeps every cross-reference of the variant, in document order within each DB
+func (variant *VariationArchive) extractXRefs() map[string][]XRef {
+	xrefs := make(map[string][]XRef)
+	for _, xref := range variant.simpleAllele().XRefList.XRef {
+		xrefs[xref.DB] = append(xrefs[xref.DB], XRef{ID: xref.ID, Type: xref.Type})
+	}
+	return xrefs
+}
+
+// firstXRefID returns the first ID cross-referenced in db, or NotProvided; it backs the legacy DbSNPID and OmimID fields
+func (variantInfo *ClinVarVariationData) firstXRefID(db string) string {
+	if xrefs := variantInfo.XRefs[db]; len(xrefs) > 0 {
+		return xrefs[0].ID
+	}
+	return NotProvided
+}
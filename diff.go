@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// variantSummary is the part of a variant diff compares between releases
+type variantSummary struct {
+	Version        string
+	Interpretation string
+	ReviewStatus   string
+}
+
+func summarizeVariant(variantInfo clinvar.ClinVarVariationData) variantSummary {
+	return variantSummary{
+		Version:        variantInfo.Version,
+		Interpretation: variantInfo.Interpretation,
+		ReviewStatus:   variantInfo.ReviewStatus}
+}
+
+// variantChange is one line of diff output. Old is nil for added variants and New is nil for removed ones
+type variantChange struct {
+	Accession string
+	Change    string
+	Old       *variantSummary `json:",omitempty"`
+	New       *variantSummary `json:",omitempty"`
+}
+
+func runDiff(args []string) error {
+	flags := newFlagSet("diff")
+	oldFile := flags.String("old", "", "Path of the earlier ClinVar release XML")
+	newFile := flags.String("new", "", "Path of the later ClinVar release XML")
+	outputFile := flags.String("o", "", "Path of file to write the changes to as ndjson (stdout when empty)")
+	workers := flags.Int("workers", 1, "Number of goroutines extracting variants in parallel")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *oldFile == "" || *newFile == "" {
+		return usageErrorf(flags, "both -old and -new are required")
+	}
+	opts := streamOptions{Workers: *workers, Assembly: clinvar.DefaultAssembly, MaxAlleleFrequency: 1}
+
+	//Only the summaries of the old release are held in memory; the new one is streamed against them
+	oldVariants := make(map[string]variantSummary)
+	err := opts.extractRelease(*oldFile, func(variantInfo clinvar.ClinVarVariationData) error {
+		oldVariants[variantInfo.Accesssion] = summarizeVariant(variantInfo)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := createOutputFile(*outputFile)
+	if err != nil {
+		return err
+	}
+	buffered := bufio.NewWriter(out)
+	encoder := json.NewEncoder(buffered)
+	var added, removed, changed int
+	err = opts.extractRelease(*newFile, func(variantInfo clinvar.ClinVarVariationData) error {
+		summary := summarizeVariant(variantInfo)
+		old, ok := oldVariants[variantInfo.Accesssion]
+		delete(oldVariants, variantInfo.Accesssion)
+		switch {
+		case !ok:
+			added++
+			return encoder.Encode(variantChange{Accession: variantInfo.Accesssion, Change: "added", New: &summary})
+		case old != summary:
+			changed++
+			return encoder.Encode(variantChange{Accession: variantInfo.Accesssion, Change: "changed", Old: &old, New: &summary})
+		}
+		return nil
+	})
+
+	//Whatever is left of the old release was not found in the new one
+	if err == nil {
+		accessions := make([]string, 0, len(oldVariants))
+		for accession := range oldVariants {
+			accessions = append(accessions, accession)
+		}
+		sort.Strings(accessions)
+		for _, accession := range accessions {
+			old := oldVariants[accession]
+			if err = encoder.Encode(variantChange{Accession: accession, Change: "removed", Old: &old}); err != nil {
+				break
+			}
+			removed++
+		}
+	}
+	if flushErr := buffered.Flush(); err == nil {
+		err = flushErr
+	}
+	if out != os.Stdout {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d added, %d removed, %d changed\n", added, removed, changed)
+	return nil
+}
+
+// extractRelease opens a release and hands each of its variants that pass the output filters to emit
+func (opts streamOptions) extractRelease(file string, emit func(clinvar.ClinVarVariationData) error) error {
+	reader, variantFile, err := openRelease(file)
+	if err != nil {
+		return err
+	}
+	defer variantFile.Close()
+	return opts.extractVariants(reader, emit)
+}
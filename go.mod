@@ -0,0 +1,3 @@
+module github.com/SowmithDaram/clinvar-xml-parser
+
+go 1.23
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+var indexColumns = []string{"Accession", "VariationID", "Version", "Offset", "GeneSymbols", "DbSNPID", "Chr", "Start", "Stop"}
+
+// indexEntry locates one VariationArchive in an uncompressed release. GeneSymbols are separated by ';'
+// and the coordinates are those on the assembly the index was built for
+type indexEntry struct {
+	Accession   string
+	VariationID string
+	Version     string
+	Offset      int64
+	GeneSymbols string
+	DbSNPID     string
+	Chr         string
+	Start       int
+	Stop        int
+}
+
+func newIndexEntry(variant *clinvar.VariationArchive, offset int64, assembly string) indexEntry {
+	variantInfo := clinvar.Extract(variant, assembly)
+	entry := indexEntry{
+		Accession:   variant.Accession,
+		VariationID: variant.VariationID,
+		Version:     variant.Version,
+		Offset:      offset,
+		DbSNPID:     variantInfo.DbSNPID}
+	symbols := []string{}
+	for _, gene := range variantInfo.Genes {
+		symbols = append(symbols, gene.Symbol)
+	}
+	entry.GeneSymbols = strings.Join(symbols, ";")
+	for _, location := range variantInfo.Locations {
+		if location.Assembly == assembly {
+			entry.Chr, entry.Start, entry.Stop = location.Chr, location.Start, location.Stop
+			break
+		}
+	}
+	return entry
+}
+
+func (entry indexEntry) record() []string {
+	return []string{
+		entry.Accession,
+		entry.VariationID,
+		entry.Version,
+		strconv.FormatInt(entry.Offset, 10),
+		entry.GeneSymbols,
+		entry.DbSNPID,
+		entry.Chr,
+		strconv.Itoa(entry.Start),
+		strconv.Itoa(entry.Stop)}
+}
+
+// buildIndex scans a release and hands the index entry of every VariationArchive to emit
+func buildIndex(reader *clinvar.Reader, assembly string, emit func(indexEntry) error) error {
+	return forEachVariationArchive(reader, func(variant *clinvar.VariationArchive) error {
+		return emit(newIndexEntry(variant, reader.Offset(), assembly))
+	})
+}
+
+// readIndex loads an index written by the index command
+func readIndex(file string) ([]indexEntry, error) {
+	indexFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer indexFile.Close()
+
+	records := csv.NewReader(indexFile)
+	records.Comma = '\t'
+	records.FieldsPerRecord = len(indexColumns)
+	if _, err := records.Read(); err != nil {
+		return nil, fmt.Errorf("index %s: %w", file, err)
+	}
+	entries := []indexEntry{}
+	for {
+		record, err := records.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", file, err)
+		}
+		entry := indexEntry{
+			Accession:   record[0],
+			VariationID: record[1],
+			Version:     record[2],
+			GeneSymbols: record[4],
+			DbSNPID:     record[5],
+			Chr:         record[6]}
+		entry.Offset, err = strconv.ParseInt(record[3], 10, 64)
+		if err == nil {
+			entry.Start, err = strconv.Atoi(record[7])
+		}
+		if err == nil {
+			entry.Stop, err = strconv.Atoi(record[8])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("index %s: %w", file, err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// openUncompressedRelease opens a release that is read at random offsets, which needs the file to be uncompressed
+func openUncompressedRelease(file string) (*os.File, error) {
+	variantFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := clinvar.IsCompressed(variantFile)
+	if err == nil && compressed {
+		err = fmt.Errorf("%s is compressed; offsets can only be read from an uncompressed release", file)
+	}
+	if err != nil {
+		variantFile.Close()
+		return nil, err
+	}
+	return variantFile, nil
+}
+
+func runIndex(args []string) error {
+	flags := newFlagSet("index")
+	var in inputFlags
+	in.register(flags)
+	outputFile := flags.String("o", "", "Path of file to write the tab-separated index to (stdout when empty)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if in.inputXML == "" {
+		return usageErrorf(flags, "-i is required: offsets can only be taken from a file")
+	}
+
+	variantFile, err := openUncompressedRelease(in.inputXML)
+	if err != nil {
+		return err
+	}
+	defer variantFile.Close()
+	reader, err := clinvar.NewReader(variantFile)
+	if err != nil {
+		return err
+	}
+
+	out, err := createOutputFile(*outputFile)
+	if err != nil {
+		return err
+	}
+	records := csv.NewWriter(out)
+	records.Comma = '\t'
+	err = records.Write(indexColumns)
+	if err == nil {
+		err = buildIndex(reader, in.assembly, func(entry indexEntry) error {
+			return records.Write(entry.record())
+		})
+	}
+	records.Flush()
+	if err == nil {
+		err = records.Error()
+	}
+	if out != os.Stdout {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -1,815 +1,380 @@
 package main
 
 import (
-	"encoding/json"
-	"encoding/xml"
+	"bufio"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
-	"github.com/davecgh/go-spew/spew"
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
 )
 
-type ClinVarDataRelease struct {
-	XMLName xml.Name `xml:"ClinVarResult-Set"`
-	//Text                      string             `xml:",chardata"`
-	//Xsi                       string             `xml:"xsi,attr"`
-	//NoNamespaceSchemaLocation string             `xml:"noNamespaceSchemaLocation,attr"`
-	//ReleaseDate               string             `xml:"ReleaseDate,attr"`
-	Variants []VariationArchive `xml:"VariationArchive"`
-}
+// Exit codes: 2 is reserved for mistakes on the command line itself
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
 
-type VariationArchive struct {
-	Text                string `xml:",chardata"`
-	VariationID         string `xml:"VariationID,attr"`
-	VariationName       string `xml:"VariationName,attr"`
-	VariationType       string `xml:"VariationType,attr"`
-	DateCreated         string `xml:"DateCreated,attr"`
-	DateLastUpdated     string `xml:"DateLastUpdated,attr"`
-	Accession           string `xml:"Accession,attr"`
-	Version             string `xml:"Version,attr"`
-	RecordType          string `xml:"RecordType,attr"`
-	NumberOfSubmissions string `xml:"NumberOfSubmissions,attr"`
-	NumberOfSubmitters  string `xml:"NumberOfSubmitters,attr"`
-	RecordStatus        string `xml:"RecordStatus"`
-	Species             string `xml:"Species"`
-	InterpretedRecord   struct {
-		Text         string `xml:",chardata"`
-		SimpleAllele struct {
-			Text        string `xml:",chardata"`
-			AlleleID    string `xml:"AlleleID,attr"`
-			VariationID string `xml:"VariationID,attr"`
-			GeneList    struct {
-				Text string `xml:",chardata"`
-				Gene []struct {
-					Text             string `xml:",chardata"`
-					Symbol           string `xml:"Symbol,attr"`
-					FullName         string `xml:"FullName,attr"`
-					GeneID           string `xml:"GeneID,attr"`
-					HGNCID           string `xml:"HGNC_ID,attr"`
-					Source           string `xml:"Source,attr"`
-					RelationshipType string `xml:"RelationshipType,attr"`
-					Location         struct {
-						Text                string `xml:",chardata"`
-						CytogeneticLocation string `xml:"CytogeneticLocation"`
-						SequenceLocation    []struct {
-							Text                     string `xml:",chardata"`
-							Assembly                 string `xml:"Assembly,attr"`
-							AssemblyAccessionVersion string `xml:"AssemblyAccessionVersion,attr"`
-							AssemblyStatus           string `xml:"AssemblyStatus,attr"`
-							Chr                      string `xml:"Chr,attr"`
-							Accession                string `xml:"Accession,attr"`
-							Start                    string `xml:"start,attr"`
-							Stop                     string `xml:"stop,attr"`
-							DisplayStart             string `xml:"display_start,attr"`
-							DisplayStop              string `xml:"display_stop,attr"`
-							Strand                   string `xml:"Strand,attr"`
-						} `xml:"SequenceLocation"`
-					} `xml:"Location"`
-					OMIM               string `xml:"OMIM"`
-					Haploinsufficiency struct {
-						Text          string `xml:",chardata"`
-						LastEvaluated string `xml:"last_evaluated,attr"`
-						ClinGen       string `xml:"ClinGen,attr"`
-					} `xml:"Haploinsufficiency"`
-					Triplosensitivity struct {
-						Text          string `xml:",chardata"`
-						LastEvaluated string `xml:"last_evaluated,attr"`
-						ClinGen       string `xml:"ClinGen,attr"`
-					} `xml:"Triplosensitivity"`
-					Property []string `xml:"Property"`
-				} `xml:"Gene"`
-			} `xml:"GeneList"`
-			Name     string `xml:"Name"`
-			Type     string `xml:"Type"`
-			Location struct {
-				Text                string `xml:",chardata"`
-				CytogeneticLocation string `xml:"CytogeneticLocation"`
-				SequenceLocation    []struct {
-					Text                     string `xml:",chardata"`
-					Assembly                 string `xml:"Assembly,attr"`
-					AssemblyAccessionVersion string `xml:"AssemblyAccessionVersion,attr"`
-					ForDisplay               string `xml:"forDisplay,attr"`
-					AssemblyStatus           string `xml:"AssemblyStatus,attr"`
-					Chr                      string `xml:"Chr,attr"`
-					Accession                string `xml:"Accession,attr"`
-					Start                    string `xml:"start,attr"`
-					Stop                     string `xml:"stop,attr"`
-					DisplayStart             string `xml:"display_start,attr"`
-					DisplayStop              string `xml:"display_stop,attr"`
-					Length                   string `xml:"Length,attr"`
-					PositionVCF              string `xml:"positionVCF,attr"`
-					ReferenceAlleleVCF       string `xml:"referenceAlleleVCF,attr"`
-					AlternateAlleleVCF       string `xml:"alternateAlleleVCF,attr"`
-				} `xml:"SequenceLocation"`
-			} `xml:"Location"`
-			OtherNameList struct {
-				Text string   `xml:",chardata"`
-				Name []string `xml:"Name"`
-			} `xml:"OtherNameList"`
-			XRefList struct {
-				Text string `xml:",chardata"`
-				XRef []struct {
-					Text string `xml:",chardata"`
-					Type string `xml:"Type,attr"`
-					ID   string `xml:"ID,attr"`
-					DB   string `xml:"DB,attr"`
-				} `xml:"XRef"`
-			} `xml:"XRefList"`
-			CanonicalSPDI string `xml:"CanonicalSPDI"`
-			HGVSlist      struct {
-				Text string `xml:",chardata"`
-				HGVS []struct {
-					Text                 string `xml:",chardata"`
-					Type                 string `xml:"Type,attr"`
-					Assembly             string `xml:"Assembly,attr"`
-					NucleotideExpression struct {
-						Text                     string `xml:",chardata"`
-						SequenceAccessionVersion string `xml:"sequenceAccessionVersion,attr"`
-						SequenceAccession        string `xml:"sequenceAccession,attr"`
-						SequenceVersion          string `xml:"sequenceVersion,attr"`
-						Change                   string `xml:"change,attr"`
-						Assembly                 string `xml:"Assembly,attr"`
-						MANESelect               string `xml:"MANESelect,attr"`
-						Expression               string `xml:"Expression"`
-					} `xml:"NucleotideExpression"`
-					MolecularConsequence struct {
-						Text string `xml:",chardata"`
-						ID   string `xml:"ID,attr"`
-						Type string `xml:"Type,attr"`
-						DB   string `xml:"DB,attr"`
-					} `xml:"MolecularConsequence"`
-					ProteinExpression struct {
-						Text                     string `xml:",chardata"`
-						SequenceAccessionVersion string `xml:"sequenceAccessionVersion,attr"`
-						SequenceAccession        string `xml:"sequenceAccession,attr"`
-						SequenceVersion          string `xml:"sequenceVersion,attr"`
-						Change                   string `xml:"change,attr"`
-						Expression               string `xml:"Expression"`
-					} `xml:"ProteinExpression"`
-				} `xml:"HGVS"`
-			} `xml:"HGVSlist"`
-			AlleleFrequencyList struct {
-				Text            string `xml:",chardata"`
-				AlleleFrequency []struct {
-					Text   string `xml:",chardata"`
-					Value  string `xml:"Value,attr"`
-					Source string `xml:"Source,attr"`
-				} `xml:"AlleleFrequency"`
-			} `xml:"AlleleFrequencyList"`
-			GlobalMinorAlleleFrequency struct {
-				Text        string `xml:",chardata"`
-				Value       string `xml:"Value,attr"`
-				Source      string `xml:"Source,attr"`
-				MinorAllele string `xml:"MinorAllele,attr"`
-			} `xml:"GlobalMinorAlleleFrequency"`
-			ProteinChange []string `xml:"ProteinChange"`
-		} `xml:"SimpleAllele"`
-		ReviewStatus string `xml:"ReviewStatus"`
-		RCVList      struct {
-			Text         string `xml:",chardata"`
-			RCVAccession []struct {
-				Text                     string `xml:",chardata"`
-				Title                    string `xml:"Title,attr"`
-				DateLastEvaluated        string `xml:"DateLastEvaluated,attr"`
-				ReviewStatus             string `xml:"ReviewStatus,attr"`
-				Interpretation           string `xml:"Interpretation,attr"`
-				SubmissionCount          string `xml:"SubmissionCount,attr"`
-				Accession                string `xml:"Accession,attr"`
-				Version                  string `xml:"Version,attr"`
-				InterpretedConditionList struct {
-					Text                 string `xml:",chardata"`
-					TraitSetID           string `xml:"TraitSetID,attr"`
-					InterpretedCondition struct {
-						Text string `xml:",chardata"`
-						DB   string `xml:"DB,attr"`
-						ID   string `xml:"ID,attr"`
-					} `xml:"InterpretedCondition"`
-				} `xml:"InterpretedConditionList"`
-			} `xml:"RCVAccession"`
-		} `xml:"RCVList"`
-		Interpretations struct {
-			Text           string `xml:",chardata"`
-			Interpretation struct {
-				Text                string `xml:",chardata"`
-				DateLastEvaluated   string `xml:"DateLastEvaluated,attr"`
-				NumberOfSubmissions string `xml:"NumberOfSubmissions,attr"`
-				NumberOfSubmitters  string `xml:"NumberOfSubmitters,attr"`
-				Type                string `xml:"Type,attr"`
-				Description         string `xml:"Description"`
-				Citation            []struct {
-					Text string `xml:",chardata"`
-					Type string `xml:"Type,attr"`
-					ID   struct {
-						Text   string `xml:",chardata"`
-						Source string `xml:"Source,attr"`
-					} `xml:"ID"`
-					URL string `xml:"URL"`
-				} `xml:"Citation"`
-				ConditionList struct {
-					Text     string `xml:",chardata"`
-					TraitSet []struct {
-						Text  string `xml:",chardata"`
-						ID    string `xml:"ID,attr"`
-						Type  string `xml:"Type,attr"`
-						Trait struct {
-							Text string `xml:",chardata"`
-							ID   string `xml:"ID,attr"`
-							Type string `xml:"Type,attr"`
-							Name []struct {
-								Text         string `xml:",chardata"`
-								ElementValue struct {
-									Text string `xml:",chardata"`
-									Type string `xml:"Type,attr"`
-								} `xml:"ElementValue"`
-								XRef []struct {
-									Text string `xml:",chardata"`
-									Type string `xml:"Type,attr"`
-									ID   string `xml:"ID,attr"`
-									DB   string `xml:"DB,attr"`
-								} `xml:"XRef"`
-							} `xml:"Name"`
-							XRef []struct {
-								Text string `xml:",chardata"`
-								ID   string `xml:"ID,attr"`
-								DB   string `xml:"DB,attr"`
-								Type string `xml:"Type,attr"`
-							} `xml:"XRef"`
-							Symbol []struct {
-								Text         string `xml:",chardata"`
-								ElementValue struct {
-									Text string `xml:",chardata"`
-									Type string `xml:"Type,attr"`
-								} `xml:"ElementValue"`
-								XRef struct {
-									Text string `xml:",chardata"`
-									ID   string `xml:"ID,attr"`
-									DB   string `xml:"DB,attr"`
-									Type string `xml:"Type,attr"`
-								} `xml:"XRef"`
-							} `xml:"Symbol"`
-							AttributeSet []struct {
-								Text      string `xml:",chardata"`
-								Attribute struct {
-									Text         string `xml:",chardata"`
-									Type         string `xml:"Type,attr"`
-									IntegerValue string `xml:"integerValue,attr"`
-								} `xml:"Attribute"`
-								XRef struct {
-									Text string `xml:",chardata"`
-									ID   string `xml:"ID,attr"`
-									DB   string `xml:"DB,attr"`
-								} `xml:"XRef"`
-							} `xml:"AttributeSet"`
-							Citation []struct {
-								Text   string `xml:",chardata"`
-								Type   string `xml:"Type,attr"`
-								Abbrev string `xml:"Abbrev,attr"`
-								ID     []struct {
-									Text   string `xml:",chardata"`
-									Source string `xml:"Source,attr"`
-								} `xml:"ID"`
-							} `xml:"Citation"`
-						} `xml:"Trait"`
-					} `xml:"TraitSet"`
-				} `xml:"ConditionList"`
-				DescriptionHistory []struct {
-					Text        string `xml:",chardata"`
-					Dated       string `xml:"Dated,attr"`
-					Description string `xml:"Description"`
-				} `xml:"DescriptionHistory"`
-			} `xml:"Interpretation"`
-		} `xml:"Interpretations"`
-		ClinicalAssertionList struct {
-			Text              string `xml:",chardata"`
-			ClinicalAssertion []struct {
-				Text                  string `xml:",chardata"`
-				ID                    string `xml:"ID,attr"`
-				DateCreated           string `xml:"DateCreated,attr"`
-				DateLastUpdated       string `xml:"DateLastUpdated,attr"`
-				SubmissionDate        string `xml:"SubmissionDate,attr"`
-				FDARecognizedDatabase string `xml:"FDARecognizedDatabase,attr"`
-				ClinVarSubmissionID   struct {
-					Text                string `xml:",chardata"`
-					LocalKey            string `xml:"localKey,attr"`
-					Title               string `xml:"title,attr"`
-					LocalKeyIsSubmitted string `xml:"localKeyIsSubmitted,attr"`
-					SubmittedAssembly   string `xml:"submittedAssembly,attr"`
-				} `xml:"ClinVarSubmissionID"`
-				ClinVarAccession struct {
-					Text                 string `xml:",chardata"`
-					Accession            string `xml:"Accession,attr"`
-					Type                 string `xml:"Type,attr"`
-					Version              string `xml:"Version,attr"`
-					SubmitterName        string `xml:"SubmitterName,attr"`
-					OrgID                string `xml:"OrgID,attr"`
-					OrganizationCategory string `xml:"OrganizationCategory,attr"`
-					OrgAbbreviation      string `xml:"OrgAbbreviation,attr"`
-				} `xml:"ClinVarAccession"`
-				RecordStatus   string `xml:"RecordStatus"`
-				ReviewStatus   string `xml:"ReviewStatus"`
-				Interpretation struct {
-					Text              string `xml:",chardata"`
-					DateLastEvaluated string `xml:"DateLastEvaluated,attr"`
-					Description       string `xml:"Description"`
-					Citation          []struct {
-						Text string `xml:",chardata"`
-						ID   struct {
-							Text   string `xml:",chardata"`
-							Source string `xml:"Source,attr"`
-						} `xml:"ID"`
-						URL string `xml:"URL"`
-					} `xml:"Citation"`
-					Comment struct {
-						Text string `xml:",chardata"`
-						Type string `xml:"Type,attr"`
-					} `xml:"Comment"`
-				} `xml:"Interpretation"`
-				Assertion      string `xml:"Assertion"`
-				ObservedInList struct {
-					Text       string `xml:",chardata"`
-					ObservedIn struct {
-						Text   string `xml:",chardata"`
-						Sample struct {
-							Text    string `xml:",chardata"`
-							Origin  string `xml:"Origin"`
-							Species struct {
-								Text       string `xml:",chardata"`
-								TaxonomyId string `xml:"TaxonomyId,attr"`
-							} `xml:"Species"`
-							AffectedStatus string `xml:"AffectedStatus"`
-							NumberTested   string `xml:"NumberTested"`
-						} `xml:"Sample"`
-						Method struct {
-							Text         string `xml:",chardata"`
-							MethodType   string `xml:"MethodType"`
-							TypePlatform string `xml:"TypePlatform"`
-						} `xml:"Method"`
-						ObservedData struct {
-							Text      string `xml:",chardata"`
-							Attribute struct {
-								Text         string `xml:",chardata"`
-								Type         string `xml:"Type,attr"`
-								IntegerValue string `xml:"integerValue,attr"`
-							} `xml:"Attribute"`
-							Citation []struct {
-								Text string `xml:",chardata"`
-								ID   struct {
-									Text   string `xml:",chardata"`
-									Source string `xml:"Source,attr"`
-								} `xml:"ID"`
-							} `xml:"Citation"`
-							XRef struct {
-								Text string `xml:",chardata"`
-								DB   string `xml:"DB,attr"`
-								ID   string `xml:"ID,attr"`
-								Type string `xml:"Type,attr"`
-							} `xml:"XRef"`
-						} `xml:"ObservedData"`
-					} `xml:"ObservedIn"`
-				} `xml:"ObservedInList"`
-				SimpleAllele struct {
-					Text     string `xml:",chardata"`
-					GeneList struct {
-						Text string `xml:",chardata"`
-						Gene struct {
-							Text   string `xml:",chardata"`
-							Symbol string `xml:"Symbol,attr"`
-						} `xml:"Gene"`
-					} `xml:"GeneList"`
-					Name          string `xml:"Name"`
-					Type          string `xml:"Type"`
-					OtherNameList struct {
-						Text string `xml:",chardata"`
-						Name struct {
-							Text string `xml:",chardata"`
-							Type string `xml:"Type,attr"`
-						} `xml:"Name"`
-					} `xml:"OtherNameList"`
-					XRefList struct {
-						Text string `xml:",chardata"`
-						XRef struct {
-							Text string `xml:",chardata"`
-							DB   string `xml:"DB,attr"`
-							ID   string `xml:"ID,attr"`
-							Type string `xml:"Type,attr"`
-						} `xml:"XRef"`
-					} `xml:"XRefList"`
-					AttributeSet struct {
-						Text      string `xml:",chardata"`
-						Attribute struct {
-							Text string `xml:",chardata"`
-							Type string `xml:"Type,attr"`
-						} `xml:"Attribute"`
-					} `xml:"AttributeSet"`
-					Location struct {
-						Text             string `xml:",chardata"`
-						SequenceLocation struct {
-							Text            string `xml:",chardata"`
-							Assembly        string `xml:"Assembly,attr"`
-							Chr             string `xml:"Chr,attr"`
-							AlternateAllele string `xml:"alternateAllele,attr"`
-							ReferenceAllele string `xml:"referenceAllele,attr"`
-							Start           string `xml:"start,attr"`
-							Stop            string `xml:"stop,attr"`
-							Length          string `xml:"Length,attr"`
-						} `xml:"SequenceLocation"`
-					} `xml:"Location"`
-				} `xml:"SimpleAllele"`
-				TraitSet struct {
-					Text  string `xml:",chardata"`
-					Type  string `xml:"Type,attr"`
-					Trait struct {
-						Text string `xml:",chardata"`
-						Type string `xml:"Type,attr"`
-						Name struct {
-							Text         string `xml:",chardata"`
-							ElementValue struct {
-								Text string `xml:",chardata"`
-								Type string `xml:"Type,attr"`
-							} `xml:"ElementValue"`
-						} `xml:"Name"`
-						XRef struct {
-							Text string `xml:",chardata"`
-							DB   string `xml:"DB,attr"`
-							ID   string `xml:"ID,attr"`
-							Type string `xml:"Type,attr"`
-						} `xml:"XRef"`
-					} `xml:"Trait"`
-				} `xml:"TraitSet"`
-				AttributeSet []struct {
-					Text      string `xml:",chardata"`
-					Attribute struct {
-						Text string `xml:",chardata"`
-						Type string `xml:"Type,attr"`
-					} `xml:"Attribute"`
-					Citation struct {
-						Text string `xml:",chardata"`
-						URL  string `xml:"URL"`
-						ID   struct {
-							Text   string `xml:",chardata"`
-							Source string `xml:"Source,attr"`
-						} `xml:"ID"`
-					} `xml:"Citation"`
-				} `xml:"AttributeSet"`
-				SubmissionNameList struct {
-					Text           string `xml:",chardata"`
-					SubmissionName string `xml:"SubmissionName"`
-				} `xml:"SubmissionNameList"`
-				Comment string `xml:"Comment"`
-			} `xml:"ClinicalAssertion"`
-		} `xml:"ClinicalAssertionList"`
-		TraitMappingList struct {
-			Text         string `xml:",chardata"`
-			TraitMapping []struct {
-				Text                string `xml:",chardata"`
-				ClinicalAssertionID string `xml:"ClinicalAssertionID,attr"`
-				TraitType           string `xml:"TraitType,attr"`
-				MappingType         string `xml:"MappingType,attr"`
-				MappingValue        string `xml:"MappingValue,attr"`
-				MappingRef          string `xml:"MappingRef,attr"`
-				MedGen              struct {
-					Text string `xml:",chardata"`
-					CUI  string `xml:"CUI,attr"`
-					Name string `xml:"Name,attr"`
-				} `xml:"MedGen"`
-			} `xml:"TraitMapping"`
-		} `xml:"TraitMappingList"`
-	} `xml:"InterpretedRecord"`
-}
+// errUsage is returned by a command once a command line error has been reported along with the command's usage
+var errUsage = errors.New("usage error")
 
-type ClinVarDataReleaseInfo struct {
-	W3SchemaInfo         string
-	ClinVarSchemaVersion string
-	ClinVarReleaseDate   string
+// command is one subcommand of the CLI
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(args []string) error
 }
 
-type ClinVarVariationData struct {
-	Accesssion              string
-	Version                 string
-	Type                    string
-	GeneAffected            string
-	GeneEntrezID            string
-	GeneOmimID              string
-	NcbiRefSeq              string
-	LocationType            string
-	DbSNPID                 string
-	GenomeVersion           string
-	ChromLocation           string
-	ChromStart              string
-	ChromStop               string
-	Length                  string
-	OmimID                  string
-	ReviewStatus            string
-	HGVData                 []HGVData
-	RCVData                 []RCVData
-	ClinicalInterpretations ClinicalInterpretations
-	// ClinicalAssertions  []ClinicalAssertions
+var commands []command
+
+func init() {
+	commands = []command{
+		{"parse", "[flags]", "Convert a ClinVar release to json, ndjson, tsv, csv or vcf", runParse},
+		{"filter", "[flags]", "Convert only the variants that pass the given filters", runFilter},
+		{"stats", "[flags]", "Summarize the variants of a release", runStats},
+		{"diff", "-old FILE -new FILE [flags]", "Report variants added, removed or reclassified between two releases", runDiff},
+		{"validate", "[flags]", "Check that every record of a release decodes and is consistent", runValidate},
+		{"index", "-i FILE [flags]", "Write an index of the VariationArchive offsets of an uncompressed release", runIndex},
+		{"serve", "-i FILE [flags]", "Serve the variants of an uncompressed release over HTTP", runServe},
+		{"help", "[command]", "Show help for a command", runHelp},
+	}
 }
 
-type HGVData struct {
-	Consequence string
+func main() {
+	os.Exit(runCommand(os.Args[1:]))
 }
 
-type RCVData struct {
-	AccessionID     string
-	Version         string
-	Interpretation  string
-	Condition       string
-	SubmissionCount string
-	ReviewStatus    string
-	MedGenID        string
-	TraitSetID      string
-}
+// runCommand runs the subcommand named by the first argument and returns the process exit code.
+// Flags without a subcommand run parse, as the CLI did before it had subcommands
+func runCommand(args []string) int {
+	name := "parse"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s: unknown command %q\n\n", programName(), name)
+		printUsage(os.Stderr)
+		return exitUsage
+	}
 
-type ClinicalInterpretations struct {
-	Citations []Citations
-	Trait     []Traits
+	err := cmd.run(args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	}
+	fmt.Fprintf(os.Stderr, "%s %s: %v\n", programName(), cmd.name, err)
+	return exitFailure
 }
 
-type Citations struct {
-	CitationSource string
-	CitationID     string
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
 }
 
-type Traits struct {
-	ID               string
-	Name             string
-	Citations        []Citations
-	PhenotypicSeries string
-	MIM              string
-	MedGen           string
-	Orph             string
+func programName() string {
+	return filepath.Base(os.Args[0])
 }
 
-// type ClinicalAssertions struct {
-// 	ClinicalAssertionAccession         string
-// 	ClinicalAssertionVersion           string
-// 	ClinicalAssertionID                string
-// 	ClinicalAssertionSubmitterName     string
-// 	ClinicalAssertionInterpretation    string
-// 	ClinicalAssertionPhenoSeries       string
-// 	ClinicalAssertionMESH              string
-// 	ClinicalAssertionOrphanet          string
-// 	ClinicalAssertionMedgen            string
-// 	ClinicalAssertionMethodType        string
-// 	ClinicalAssertionSCVID             string
-// 	ClinicalAssertionSCVIDReviewStatus string
-// 	TraitMappingDisease                string
-// }
-
-func main() {
-	//Define default flag values and enable input from command line
-	inputXML := flag.String("i", "", "Path of XML file to open")
-	releaseData := flag.String("r", "", "Indication if only the ClinVar release schemas and data are desired")
-	outputFile := flag.String("o", "", "Path of file to write")
-	flag.Parse()
-
-	data, err := parseXMLFileToData(*inputXML)
-	if err != nil {
-		log.Fatal("Could not parse XML file", err)
+func printUsage(out io.Writer) {
+	fmt.Fprintf(out, "Usage: %s <command> [flags]\n\nCommands:\n", programName())
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-9s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintf(out, "\nRun '%s help <command>' for the flags of a command.\n", programName())
+}
 
-	//Obtain top-level info for ClinVar file being used
-	if *releaseData == "yes" {
-		var releaseInfo ClinVarDataReleaseInfo
-		releaseInfo = data.extractReleaseInfo()
-		spew.Dump(releaseInfo)
+func runHelp(args []string) error {
+	if len(args) == 0 {
+		printUsage(os.Stdout)
+		return nil
 	}
-
-	//Obtain top-level information for variants
-	var allVariantsData []ClinVarVariationData
-	allVariantsData = data.extractAllVariants()
-
-	jsonMarshal, err := json.Marshal(allVariantsData)
-	if err != nil {
-		fmt.Printf("Error: %s", err)
-	} else if *outputFile == "" {
-		fmt.Println(string(jsonMarshal))
-		return
-	} else {
-		writeClinVarVariationDataFile(*outputFile, jsonMarshal)
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s: unknown command %q\n", programName(), args[0])
+		return errUsage
 	}
+	return cmd.run([]string{"-h"})
+}
 
+// newFlagSet returns the flag set of a command, with help text naming the command and its arguments
+func newFlagSet(name string) *flag.FlagSet {
+	cmd, _ := findCommand(name)
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s %s %s\n\n%s.\n\nFlags:\n", programName(), cmd.name, cmd.args, cmd.summary)
+		flags.PrintDefaults()
+	}
+	return flags
 }
 
-func parseXMLFileToData(file string) (*ClinVarDataRelease, error) {
-	variantFile := os.Stdin
-	var err error
-	// Open XML file for reading
-	if len(file) > 0 {
-		variantFile, err = os.Open(file)
-		if err != nil {
-			log.Fatal(err)
-		} else {
-			fmt.Fprintln(os.Stderr, "Variant info file has opened successfully!")
+// parseFlags parses the command line of a command. The flag package reports its own errors along with the usage
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
 		}
-
+		return errUsage
 	}
-	defer variantFile.Close()
-
-	// Create XML decoder
-	xmlDecoder := xml.NewDecoder(variantFile)
-
-	//Parse XML file into the variant data struct variable (top level ClinVarDataRelease wrapper)
-	var data ClinVarDataRelease
-	err = xmlDecoder.Decode(&data)
-	if err != nil {
-		return nil, err
+	if flags.NArg() > 0 {
+		return usageErrorf(flags, "unexpected arguments: %s", strings.Join(flags.Args(), " "))
 	}
+	return nil
+}
 
-	return &data, nil
+// usageErrorf reports a command line error the way the flag package does: the message followed by the usage
+func usageErrorf(flags *flag.FlagSet, format string, args ...interface{}) error {
+	fmt.Fprintf(flags.Output(), format+"\n", args...)
+	flags.Usage()
+	return errUsage
 }
 
-func (data ClinVarDataRelease) extractReleaseInfo() ClinVarDataReleaseInfo {
-	clinRelease := ClinVarDataReleaseInfo{}
-	//clinRelease.W3SchemaInfo = data.Xsi
-	//clinRelease.ClinVarSchemaVersion = data.NoNamespaceSchemaLocation
-	//clinRelease.ClinVarReleaseDate = data.ReleaseDate
-	return clinRelease
+// inputFlags select the release every command reads
+type inputFlags struct {
+	inputXML string
+	assembly string
 }
 
-func (data *ClinVarDataRelease) extractAllVariants() []ClinVarVariationData {
-	var allVariantsInfo []ClinVarVariationData
-	for _, variant := range data.Variants {
-		singleVariantInfo := variant.extractClinVarVariantData()
-		allVariantsInfo = append(allVariantsInfo, singleVariantInfo)
-	}
-	return allVariantsInfo
+func (in *inputFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&in.inputXML, "i", "", "Path of the ClinVar release XML to read, gzip and bgzip included (stdin when empty)")
+	flags.StringVar(&in.assembly, "assembly", clinvar.DefaultAssembly, "Assembly whose location populates GenomeVersion, ChromStart, ChromStop and Length")
+}
+
+// filterFlags are the variant filters shared by the commands that extract variants
+type filterFlags struct {
+	maxAlleleFrequency float64
+	changedSince       string
+	genes              stringList
+	geneFile           string
+	regions            stringList
+	bedFile            string
+	parsedRegions      []clinvar.Region
 }
 
-func (variant *VariationArchive) extractClinVarVariantData() ClinVarVariationData {
+// stringList is a flag that can be repeated, collecting every value given
+type stringList []string
+
+func (list *stringList) String() string {
+	return strings.Join(*list, ",")
+}
 
-	singleVariantInfo := ClinVarVariationData{}
+func (list *stringList) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
 
-	singleVariantInfo.Accesssion = variant.Accession
-	singleVariantInfo.Version = variant.Version
-	singleVariantInfo.Type = variant.VariationType
+func (filters *filterFlags) register(flags *flag.FlagSet) {
+	flags.Float64Var(&filters.maxAlleleFrequency, "max-af", 1, "Only keep variants whose highest population allele frequency is at most this value")
+	flags.StringVar(&filters.changedSince, "changed-since", "", "Only keep variants whose aggregate classification changed after this date (YYYY-MM-DD)")
+	flags.Var(&filters.genes, "gene", "Only keep variants in these genes, given as comma-separated symbols, Gene IDs or HGNC IDs (repeatable)")
+	flags.StringVar(&filters.geneFile, "genes", "", "Path of a file listing genes to keep as symbols, Gene IDs or HGNC IDs, separated by commas, spaces or lines ('#' starts a comment)")
+	flags.Var(&filters.regions, "region", "Only keep variants whose location on -assembly overlaps this chr:start-end region, 1-based and inclusive (repeatable)")
+	flags.StringVar(&filters.bedFile, "bed", "", "Path of a BED file; only keep variants whose location on -assembly overlaps one of its intervals")
+}
 
-	if len(variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation) > 0 && len(variant.InterpretedRecord.SimpleAllele.GeneList.Gene) > 0 && len(variant.InterpretedRecord.SimpleAllele.GeneList.Gene[0].Location.SequenceLocation) > 0 {
-		if variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation[0].Start >= variant.InterpretedRecord.SimpleAllele.GeneList.Gene[0].Location.SequenceLocation[0].Start && variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation[0].Stop <= variant.InterpretedRecord.SimpleAllele.GeneList.Gene[0].Location.SequenceLocation[0].Stop {
-			singleVariantInfo.LocationType = "withinGene"
-		} else {
-			singleVariantInfo.LocationType = "outsideGene"
+// check validates the filter values once the flags have been parsed
+func (filters *filterFlags) check(flags *flag.FlagSet) error {
+	if filters.changedSince != "" {
+		if _, err := time.Parse("2006-01-02", filters.changedSince); err != nil {
+			return usageErrorf(flags, "invalid -changed-since date %q: use YYYY-MM-DD", filters.changedSince)
 		}
-	} else {
-		singleVariantInfo.LocationType = "notProvided"
 	}
-
-	if len(variant.InterpretedRecord.SimpleAllele.GeneList.Gene) > 0 {
-		singleVariantInfo.GeneAffected = variant.InterpretedRecord.SimpleAllele.GeneList.Gene[0].Symbol
-		singleVariantInfo.GeneEntrezID = variant.InterpretedRecord.SimpleAllele.GeneList.Gene[0].GeneID
-		singleVariantInfo.GeneOmimID = variant.InterpretedRecord.SimpleAllele.GeneList.Gene[0].OMIM
-		//TODO: spew.Dump()
-	} else {
-		singleVariantInfo.GeneAffected = "notProvided"
-		singleVariantInfo.GeneEntrezID = "notProvided"
-		singleVariantInfo.GeneOmimID = "notProvided"
+	for _, value := range filters.genes {
+		if len(splitGenes(value)) == 0 {
+			return usageErrorf(flags, "invalid -gene %q: give a symbol, Gene ID or HGNC ID", value)
+		}
 	}
+	for _, value := range filters.regions {
+		region, err := parseRegion(value)
+		if err != nil {
+			return usageErrorf(flags, "invalid -region: %v", err)
+		}
+		filters.parsedRegions = append(filters.parsedRegions, region)
+	}
+	return nil
+}
 
-	singleVariantInfo.NcbiRefSeq = variant.InterpretedRecord.SimpleAllele.CanonicalSPDI
+// any reports whether a filter was given
+func (filters *filterFlags) any() bool {
+	return filters.maxAlleleFrequency < 1 || filters.changedSince != "" || len(filters.genes) > 0 || filters.geneFile != "" ||
+		len(filters.regions) > 0 || filters.bedFile != ""
+}
 
-	if len(variant.InterpretedRecord.SimpleAllele.XRefList.XRef) > 0 {
-		for _, snpdbXref := range variant.InterpretedRecord.SimpleAllele.XRefList.XRef {
-			if snpdbXref.DB == "dbSNP" {
-				singleVariantInfo.DbSNPID = snpdbXref.ID
-			} else {
-				singleVariantInfo.DbSNPID = "notProvided"
+// apply copies the filters into the stream options, reading the gene list and BED files if they were given
+func (filters *filterFlags) apply(opts *streamOptions) error {
+	opts.MaxAlleleFrequency = filters.maxAlleleFrequency
+	opts.ChangedSince = filters.changedSince
+	if len(filters.genes) > 0 || filters.geneFile != "" {
+		opts.Genes = clinvar.NewGeneSet()
+		for _, value := range filters.genes {
+			for _, gene := range splitGenes(value) {
+				opts.Genes.Add(gene)
 			}
 		}
 	}
-
-	singleVariantInfo.ChromLocation = variant.InterpretedRecord.SimpleAllele.Location.CytogeneticLocation
-
-	if len(variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation) > 0 {
-		singleVariantInfo.GenomeVersion = variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation[0].Assembly
-		singleVariantInfo.ChromStart = variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation[0].Start
-		singleVariantInfo.ChromStop = variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation[0].Stop
-		singleVariantInfo.Length = variant.InterpretedRecord.SimpleAllele.Location.SequenceLocation[0].Length
-	} else {
-		singleVariantInfo.GenomeVersion = "notProvided"
-		singleVariantInfo.ChromStart = "notProvided"
-		singleVariantInfo.ChromStop = "notProvided"
-		singleVariantInfo.Length = "notProvided"
+	if filters.geneFile != "" {
+		genes, err := readGeneList(filters.geneFile)
+		if err != nil {
+			return err
+		}
+		for _, gene := range genes {
+			opts.Genes.Add(gene)
+		}
 	}
 
-	if len(variant.InterpretedRecord.SimpleAllele.XRefList.XRef) > 0 {
-		for _, omimXref := range variant.InterpretedRecord.SimpleAllele.XRefList.XRef {
-			if omimXref.DB == "OMIM" {
-				singleVariantInfo.OmimID = omimXref.ID
-			} else {
-				singleVariantInfo.OmimID = "notProvided"
-			}
+	regions := filters.parsedRegions
+	if filters.bedFile != "" {
+		bedRegions, err := readBED(filters.bedFile)
+		if err != nil {
+			return err
 		}
+		regions = append(regions, bedRegions...)
+	}
+	if len(filters.regions) > 0 || filters.bedFile != "" {
+		opts.Regions = clinvar.NewRegionSet(regions)
 	}
+	return nil
+}
 
-	singleVariantInfo.ReviewStatus = variant.InterpretedRecord.ReviewStatus
-
-	variantHgvConsequence := []HGVData{}
-	for _, hgvs := range variant.InterpretedRecord.SimpleAllele.HGVSlist.HGVS {
-		nc := ""
-		if hgvs.MolecularConsequence.Type != "" {
-			nc = hgvs.MolecularConsequence.Type
-			skip := false
-			for _, cons := range variantHgvConsequence {
-				oc := cons.Consequence
-				if nc == oc {
-					skip = true
-					break
-				}
-			}
-			if !skip {
-				variantHgvConsequence = append(variantHgvConsequence, HGVData{
-					Consequence: nc})
-			}
-		}
+// splitGenes splits a list of genes separated by commas or spaces
+func splitGenes(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// readGeneList reads a gene panel file. The first tab-separated column of each line lists gene symbols,
+// Gene IDs or HGNC IDs separated by commas or spaces; further columns are ignored, and lines without
+// a gene or starting with '#' are skipped, so BED-style headers and comments are allowed
+func readGeneList(file string) ([]string, error) {
+	geneFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
 	}
-	singleVariantInfo.HGVData = variantHgvConsequence
-
-	variantAllRcvs := []RCVData{}
-	for _, rcvs := range variant.InterpretedRecord.RCVList.RCVAccession {
-		if rcvs.InterpretedConditionList.InterpretedCondition.DB == "MedGen" {
-			variantAllRcvs = append(variantAllRcvs, RCVData{
-				AccessionID:     rcvs.Accession,
-				Version:         rcvs.Version,
-				Interpretation:  rcvs.Interpretation,
-				Condition:       rcvs.InterpretedConditionList.InterpretedCondition.Text,
-				SubmissionCount: rcvs.SubmissionCount,
-				ReviewStatus:    rcvs.ReviewStatus,
-				MedGenID:        rcvs.InterpretedConditionList.InterpretedCondition.ID,
-				TraitSetID:      rcvs.InterpretedConditionList.TraitSetID})
-
-		} else {
-			variantAllRcvs = append(variantAllRcvs, RCVData{
-				AccessionID:     rcvs.Accession,
-				Version:         rcvs.Version,
-				Interpretation:  rcvs.Interpretation,
-				SubmissionCount: rcvs.SubmissionCount,
-				ReviewStatus:    rcvs.ReviewStatus,
-				MedGenID:        "notProvided",
-				Condition:       "notProvided",
-				TraitSetID:      rcvs.InterpretedConditionList.TraitSetID})
+	defer geneFile.Close()
+
+	genes := []string{}
+	scanner := bufio.NewScanner(geneFile)
+	for scanner.Scan() {
+		column, _, _ := strings.Cut(scanner.Text(), "\t")
+		lineGenes := splitGenes(column)
+		if len(lineGenes) == 0 || strings.HasPrefix(lineGenes[0], "#") {
+			continue
 		}
-
+		genes = append(genes, lineGenes...)
 	}
-	singleVariantInfo.RCVData = variantAllRcvs
-
-	variantAllCitations := []Citations{}
-	for _, citations := range variant.InterpretedRecord.Interpretations.Interpretation.Citation {
-		variantAllCitations = append(variantAllCitations, Citations{
-			CitationSource: citations.ID.Source,
-			CitationID:     citations.ID.Text})
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gene list %s: %w", file, err)
+	}
+	if len(genes) == 0 {
+		return nil, fmt.Errorf("gene list %s has no genes", file)
 	}
-	singleVariantInfo.ClinicalInterpretations.Citations = variantAllCitations
+	return genes, nil
+}
 
-	variantAllTraits := []Traits{}
-	for _, trait := range variant.InterpretedRecord.Interpretations.Interpretation.ConditionList.TraitSet {
-		for _, name := range trait.Trait.Name {
-			nt := Traits{ID: trait.Trait.ID}
+// parseRegion parses a samtools-style region: "17:7668402-7687550" (1-based, inclusive, commas allowed in
+// the positions), "17:7675088" for a single position or "17" for a whole chromosome
+func parseRegion(value string) (clinvar.Region, error) {
+	chr, span, hasSpan := strings.Cut(value, ":")
+	if chr == "" {
+		return clinvar.Region{}, fmt.Errorf("%q has no chromosome", value)
+	}
+	if !hasSpan {
+		return clinvar.Region{Chr: chr, Start: 1, Stop: math.MaxInt}, nil
+	}
+	startValue, stopValue, hasStop := strings.Cut(strings.ReplaceAll(span, ",", ""), "-")
+	if !hasStop {
+		stopValue = startValue
+	}
+	start, err := strconv.Atoi(startValue)
+	if err != nil || start < 1 {
+		return clinvar.Region{}, fmt.Errorf("%q has an invalid start", value)
+	}
+	stop, err := strconv.Atoi(stopValue)
+	if err != nil || stop < start {
+		return clinvar.Region{}, fmt.Errorf("%q has an invalid end", value)
+	}
+	return clinvar.Region{Chr: chr, Start: start, Stop: stop}, nil
+}
 
-			if name.ElementValue.Type == "Preferred" {
-				nt.Name = name.ElementValue.Text
-			} else {
-				continue
-			}
-			for _, xRefTrait := range trait.Trait.XRef {
-				if xRefTrait.DB == "Orphanet" {
-					nt.Orph = xRefTrait.ID
-				} else if xRefTrait.DB == "MedGen" {
-					nt.MedGen = xRefTrait.ID
-				} else if xRefTrait.DB == "OMIM" {
-					if xRefTrait.Type == "Phenotypic series" {
-						nt.PhenotypicSeries = xRefTrait.ID
-					} else if xRefTrait.Type == "MIM" {
-						nt.MIM = xRefTrait.ID
-					}
-				}
-			}
-			for _, citations := range trait.Trait.Citation {
-				for _, citationsInfo := range citations.ID {
-					nt.Citations = append(nt.Citations, Citations{
-						CitationSource: citationsInfo.Source,
-						CitationID:     citationsInfo.Text})
-				}
-			}
-			variantAllTraits = append(variantAllTraits, nt)
+// readBED reads the intervals of a BED file, converting them from BED's 0-based, half-open coordinates.
+// Zero-length intervals (insertion sites) cover the bases on either side. Only the first three columns
+// are used; blank, '#', track and browser lines are skipped
+func readBED(file string) ([]clinvar.Region, error) {
+	bedFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer bedFile.Close()
+
+	regions := []clinvar.Region{}
+	scanner := bufio.NewScanner(bedFile)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || fields[0] == "track" || fields[0] == "browser" {
+			continue
+		}
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("BED %s line %d: expected chrom, start and end", file, lineNumber)
+		}
+		start, startErr := strconv.Atoi(fields[1])
+		end, endErr := strconv.Atoi(fields[2])
+		if startErr != nil || endErr != nil || start < 0 || end < start {
+			return nil, fmt.Errorf("BED %s line %d: invalid interval %s-%s", file, lineNumber, fields[1], fields[2])
+		}
+		if end == start {
+			//A zero-length interval marks an insertion site between two bases; keep both of them
+			regions = append(regions, clinvar.Region{Chr: fields[0], Start: start, Stop: start + 1})
+			continue
 		}
+		regions = append(regions, clinvar.Region{Chr: fields[0], Start: start + 1, Stop: end})
 	}
-	singleVariantInfo.ClinicalInterpretations.Trait = variantAllTraits
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("BED %s: %w", file, err)
+	}
+	return regions, nil
+}
 
-	return singleVariantInfo
+// openXMLFile opens the XML file for reading, falling back to stdin when no path is given.
+// Gzip and bgzip compressed input is decompressed on the fly
+func openXMLFile(file string) (io.ReadCloser, error) {
+	if len(file) == 0 {
+		return clinvar.Decompress(os.Stdin)
+	}
+	variantFile, err := clinvar.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintln(os.Stderr, "Variant info file has opened successfully!")
+	return variantFile, nil
 }
 
-func writeClinVarVariationDataFile(outputFile string, jsonMarshal []byte) {
-	out := os.Stdout
-	var err error
-	if len(outputFile) > 0 {
-		out, err = os.Create(outputFile)
-		if err != nil {
-			log.Fatal("Could not create outputfile: ", outputFile, "\n", err)
-		}
+// openRelease opens the XML file and reads the root element of the release
+func openRelease(file string) (*clinvar.Reader, io.Closer, error) {
+	variantFile, err := openXMLFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	reader, err := clinvar.NewReader(variantFile)
+	if err != nil {
+		variantFile.Close()
+		return nil, nil, err
 	}
-	defer out.Close()
-	out.WriteString(string(jsonMarshal))
+	return reader, variantFile, nil
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadGeneList(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "panel.txt")
+	panel := "# gene panel\n" +
+		"TP53\n" +
+		"\n" +
+		"BRCA1,BRCA2\n" +
+		"672, HGNC:1100\tcolumns after the first are ignored\n" +
+		", ,\n" +
+		"  #indented comment\n" +
+		"ATM\tcomment, not a gene\n"
+	if err := os.WriteFile(file, []byte(panel), 0644); err != nil {
+		t.Fatal(err)
+	}
+	genes, err := readGeneList(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"TP53", "BRCA1", "BRCA2", "672", "HGNC:1100", "ATM"}
+	if !reflect.DeepEqual(genes, want) {
+		t.Errorf("readGeneList = %q, want %q", genes, want)
+	}
+}
+
+func TestReadGeneListWithoutGenes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "empty.txt")
+	if err := os.WriteFile(file, []byte("# no genes\n,\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readGeneList(file); err == nil {
+		t.Error("readGeneList accepted a file without genes")
+	}
+}
+
+func TestSplitGenes(t *testing.T) {
+	if got, want := splitGenes("TP53,BRCA1, ATM,,"), []string{"TP53", "BRCA1", "ATM"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitGenes = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// variantWriter is implemented by every output format that can be written while variants are streamed
+type variantWriter interface {
+	//WriteReleaseInfo is called at most once, before the first variant
+	WriteReleaseInfo(releaseInfo clinvar.ClinVarDataReleaseInfo) error
+	Write(variant clinvar.ClinVarVariationData) error
+	//Close finishes the output and flushes anything buffered
+	Close() error
+}
+
+// openVariantWriter creates the output for the requested format. Tabular and VCF formats write one file
+// per table or assembly, so for them outputFile names the directory the files are written into
+func openVariantWriter(format string, outputFile string) (variantWriter, error) {
+	switch format {
+	case "tsv":
+		return newTableWriter(outputFile, '\t', ".tsv")
+	case "csv":
+		return newTableWriter(outputFile, ',', ".csv")
+	case "vcf":
+		return newVCFWriter(outputFile)
+	}
+
+	out, err := createOutputFile(outputFile)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := newVariantWriter(format, out)
+	if err != nil {
+		if out != os.Stdout {
+			out.Close()
+		}
+		return nil, err
+	}
+	return fileVariantWriter{variantWriter: writer, file: out}, nil
+}
+
+// newVariantWriter returns the writer for a single-stream output format
+func newVariantWriter(format string, w io.Writer) (variantWriter, error) {
+	switch format {
+	case "", "json":
+		return newJSONArrayWriter(w), nil
+	case "ndjson":
+		return newNDJSONWriter(w), nil
+	}
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+// fileVariantWriter closes the output file once the wrapped writer has finished, leaving stdout open
+// for whatever is written after it
+type fileVariantWriter struct {
+	variantWriter
+	file *os.File
+}
+
+func (w fileVariantWriter) Close() error {
+	writerErr := w.variantWriter.Close()
+	if w.file == os.Stdout {
+		return writerErr
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	return writerErr
+}
+
+// jsonArrayWriter emits variants one by one as the elements of a single JSON array,
+// optionally wrapped in the same ClinVarReleaseOutput shape used for non-streamed output
+type jsonArrayWriter struct {
+	out         *bufio.Writer
+	count       int
+	withRelease bool
+}
+
+func newJSONArrayWriter(w io.Writer) *jsonArrayWriter {
+	return &jsonArrayWriter{out: bufio.NewWriter(w)}
+}
+
+// WriteReleaseInfo opens a ClinVarReleaseOutput object; it must be called before the first variant is written
+func (w *jsonArrayWriter) WriteReleaseInfo(releaseInfo clinvar.ClinVarDataReleaseInfo) error {
+	jsonMarshal, err := json.Marshal(releaseInfo)
+	if err != nil {
+		return err
+	}
+	w.withRelease = true
+	_, err = fmt.Fprintf(w.out, `{"ReleaseInfo":%s,"Variants":`, jsonMarshal)
+	return err
+}
+
+func (w *jsonArrayWriter) Write(variant clinvar.ClinVarVariationData) error {
+	jsonMarshal, err := json.Marshal(variant)
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if w.count == 0 {
+		separator = "["
+	}
+	if _, err := w.out.WriteString(separator); err != nil {
+		return err
+	}
+	if _, err := w.out.Write(jsonMarshal); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Close terminates the array (an empty input still yields a valid "[]") and flushes buffered output
+func (w *jsonArrayWriter) Close() error {
+	closing := "]"
+	if w.count == 0 {
+		closing = "[]"
+	}
+	if w.withRelease {
+		closing += "}"
+	}
+	closing += "\n"
+	if _, err := w.out.WriteString(closing); err != nil {
+		return err
+	}
+	return w.out.Flush()
+}
+
+// createOutputFile returns the file to write results to, defaulting to stdout when no path is given
+func createOutputFile(outputFile string) (*os.File, error) {
+	if len(outputFile) == 0 {
+		return os.Stdout, nil
+	}
+	return os.Create(outputFile)
+}
+
+// ndjsonWriter emits one ClinVarVariationData object per line so output can be streamed, split and appended.
+// Release info, when requested, is written as a {"ReleaseInfo": ...} object on the first line
+type ndjsonWriter struct {
+	out     *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	out := bufio.NewWriter(w)
+	return &ndjsonWriter{out: out, encoder: json.NewEncoder(out)}
+}
+
+func (w *ndjsonWriter) WriteReleaseInfo(releaseInfo clinvar.ClinVarDataReleaseInfo) error {
+	return w.encoder.Encode(struct {
+		ReleaseInfo clinvar.ClinVarDataReleaseInfo
+	}{releaseInfo})
+}
+
+func (w *ndjsonWriter) Write(variant clinvar.ClinVarVariationData) error {
+	return w.encoder.Encode(variant)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return w.out.Flush()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+func TestStdoutWriterLeavesStdoutOpen(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	for _, format := range []string{"json", "ndjson"} {
+		writer, err := openVariantWriter(format, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatalf("%s: Close: %v", format, err)
+		}
+	}
+	if _, err := io.WriteString(os.Stdout, "after\n"); err != nil {
+		t.Fatalf("stdout closed by the variant writers: %v", err)
+	}
+	w.Close()
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[]\nafter\n"; string(got) != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+}
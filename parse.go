@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// outputFlags control how parse and filter write the extracted variants
+type outputFlags struct {
+	outputFile  string
+	format      string
+	releaseInfo bool
+	releaseData string
+	streamMode  bool
+	workers     int
+}
+
+func (out *outputFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&out.outputFile, "o", "", "Path of file to write (stdout when empty); the directory to write into for tsv, csv and vcf")
+	flags.StringVar(&out.format, "format", "json", "Output format: json, ndjson, tsv, csv or vcf (all but json imply -s; tsv/csv/vcf write one file per table or assembly into the -o directory)")
+	flags.BoolVar(&out.releaseInfo, "release", false, "Output the ClinVar release schema and date alongside the variants")
+	flags.StringVar(&out.releaseData, "r", "", "Same as -release when set to yes (kept for existing scripts)")
+	flags.BoolVar(&out.streamMode, "s", false, "Stream variants one at a time instead of loading the whole release into memory")
+	flags.IntVar(&out.workers, "workers", 1, "Number of goroutines extracting variants in parallel (implies -s when greater than 1)")
+}
+
+func runParse(args []string) error {
+	return convert("parse", args, false)
+}
+
+func runFilter(args []string) error {
+	return convert("filter", args, true)
+}
+
+// convert implements parse and filter, which differ only in filter requiring at least one filter
+func convert(name string, args []string, requireFilter bool) error {
+	flags := newFlagSet(name)
+	var in inputFlags
+	var out outputFlags
+	var filters filterFlags
+	in.register(flags)
+	out.register(flags)
+	filters.register(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := filters.check(flags); err != nil {
+		return err
+	}
+	if requireFilter && !filters.any() {
+		return usageErrorf(flags, "no filter given; use parse to convert every variant")
+	}
+
+	opts := streamOptions{
+		WithReleaseInfo: out.releaseInfo || out.releaseData == "yes",
+		Workers:         out.workers,
+		Format:          out.format,
+		Assembly:        in.assembly}
+	if err := filters.apply(&opts); err != nil {
+		return err
+	}
+
+	//Streaming keeps memory flat for full-size releases by never building the complete ClinVarDataRelease.
+	//Gene and region filters always stream, so the records they skip are never held in memory
+	if out.streamMode || out.workers > 1 || out.format != "json" || opts.selective() {
+		return streamXMLFileToOutput(in.inputXML, out.outputFile, opts)
+	}
+	return convertXMLFile(in.inputXML, out.outputFile, opts)
+}
+
+// convertXMLFile loads the whole release into memory and writes the variants as one JSON document
+func convertXMLFile(inputFile string, outputFile string, opts streamOptions) error {
+	data, err := parseXMLFileToData(inputFile)
+	if err != nil {
+		return fmt.Errorf("could not parse XML file: %w", err)
+	}
+
+	//Obtain top-level information for variants
+	allVariantsData := []clinvar.ClinVarVariationData{}
+	for _, singleVariantInfo := range data.ExtractAll(opts.Assembly) {
+		if opts.keep(singleVariantInfo) {
+			allVariantsData = append(allVariantsData, singleVariantInfo)
+		}
+	}
+
+	var output interface{} = allVariantsData
+	//Obtain top-level info for ClinVar file being used
+	if opts.WithReleaseInfo {
+		output = clinvar.ClinVarReleaseOutput{
+			ReleaseInfo: data.ReleaseInfo(),
+			Variants:    allVariantsData}
+	}
+
+	jsonMarshal, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	if outputFile == "" {
+		_, err = fmt.Println(string(jsonMarshal))
+		return err
+	}
+	return writeClinVarVariationDataFile(outputFile, jsonMarshal)
+}
+
+func parseXMLFileToData(file string) (*clinvar.ClinVarDataRelease, error) {
+	variantFile, err := openXMLFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defer variantFile.Close()
+
+	return clinvar.Parse(variantFile)
+}
+
+func writeClinVarVariationDataFile(outputFile string, jsonMarshal []byte) error {
+	out, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("could not create output file %s: %w", outputFile, err)
+	}
+	if _, err := out.Write(jsonMarshal); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
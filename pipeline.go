@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// variantJob carries a decoded VariationArchive along with its position in the input
+type variantJob struct {
+	index   int
+	variant *clinvar.VariationArchive
+}
+
+// variantResult carries an extracted variant back to the ordered writer
+type variantResult struct {
+	index int
+	data  clinvar.ClinVarVariationData
+}
+
+// extractVariantsConcurrently runs decode on one goroutine, which hands it each VariationArchive to extract,
+// transforms them into ClinVarVariationData with extract on a pool of workers and hands the results to emit in input order.
+// The first decode or emit error cancels the whole pipeline and is returned
+func extractVariantsConcurrently(decode func(handle func(*clinvar.VariationArchive) error) error, workers int, extract func(*clinvar.VariationArchive) clinvar.ClinVarVariationData, emit func(clinvar.ClinVarVariationData) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	jobs := make(chan variantJob, workers)
+	results := make(chan variantResult, workers)
+
+	//Decoder: a single goroutine owns the reader behind decode
+	var decoderDone sync.WaitGroup
+	decoderDone.Add(1)
+	go func() {
+		defer decoderDone.Done()
+		defer close(jobs)
+		index := 0
+		err := decode(func(variant *clinvar.VariationArchive) error {
+			select {
+			case jobs <- variantJob{index: index, variant: variant}:
+				index++
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && err != context.Canceled {
+			fail(err)
+		}
+	}()
+
+	//Workers: extract variants in parallel, in whatever order they finish
+	var workersDone sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		workersDone.Add(1)
+		go func() {
+			defer workersDone.Done()
+			for job := range jobs {
+				result := variantResult{index: job.index, data: extract(job.variant)}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workersDone.Wait()
+		close(results)
+	}()
+
+	//Ordered writer: hold back results that finish early until every earlier variant has been emitted
+	pending := make(map[int]clinvar.ClinVarVariationData)
+	next := 0
+	for result := range results {
+		if ctx.Err() != nil {
+			continue
+		}
+		pending[result.index] = result.data
+		for data, ok := pending[next]; ok; data, ok = pending[next] {
+			delete(pending, next)
+			next++
+			if err := emit(data); err != nil {
+				fail(err)
+				break
+			}
+		}
+	}
+	decoderDone.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// decodeArchives returns a decode function handing out count archives numbered from 0, failing with err
+// instead of decoding the archive numbered failAt
+func decodeArchives(count int, failAt int, err error) func(handle func(*clinvar.VariationArchive) error) error {
+	return func(handle func(*clinvar.VariationArchive) error) error {
+		for i := 0; i < count; i++ {
+			if i == failAt {
+				return err
+			}
+			if err := handle(&clinvar.VariationArchive{Accession: strconv.Itoa(i)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// extractSlowly finishes extractions in a random order, so results only come out in order if the pipeline reorders them
+func extractSlowly(variant *clinvar.VariationArchive) clinvar.ClinVarVariationData {
+	time.Sleep(time.Duration(rand.Intn(200)) * time.Microsecond)
+	return clinvar.ClinVarVariationData{Accesssion: variant.Accession}
+}
+
+// runPipeline fails the test if the pipeline has not returned within a few seconds rather than hanging
+func runPipeline(t *testing.T, decode func(handle func(*clinvar.VariationArchive) error) error, workers int, emit func(clinvar.ClinVarVariationData) error) error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- extractVariantsConcurrently(decode, workers, extractSlowly, emit)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("pipeline deadlocked")
+		return nil
+	}
+}
+
+func TestExtractVariantsConcurrentlyKeepsInputOrder(t *testing.T) {
+	for _, workers := range []int{0, 1, 2, 8} {
+		var emitted []string
+		err := runPipeline(t, decodeArchives(500, -1, nil), workers, func(variant clinvar.ClinVarVariationData) error {
+			emitted = append(emitted, variant.Accesssion)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("workers %d: %v", workers, err)
+		}
+		if len(emitted) != 500 {
+			t.Fatalf("workers %d: emitted %d variants, want 500", workers, len(emitted))
+		}
+		for i, accession := range emitted {
+			if accession != strconv.Itoa(i) {
+				t.Fatalf("workers %d: variant %d emitted as %s", workers, i, accession)
+			}
+		}
+	}
+}
+
+func TestExtractVariantsConcurrentlyStopsOnDecodeError(t *testing.T) {
+	decodeErr := errors.New("decode failed")
+	var emitted int
+	err := runPipeline(t, decodeArchives(500, 100, decodeErr), 4, func(variant clinvar.ClinVarVariationData) error {
+		if variant.Accesssion != strconv.Itoa(emitted) {
+			t.Errorf("variant %d emitted as %s", emitted, variant.Accesssion)
+		}
+		emitted++
+		return nil
+	})
+	if !errors.Is(err, decodeErr) {
+		t.Fatalf("got error %v, want %v", err, decodeErr)
+	}
+	if emitted > 100 {
+		t.Errorf("emitted %d variants, but decoding failed at the 101st", emitted)
+	}
+}
+
+func TestExtractVariantsConcurrentlyStopsOnEmitError(t *testing.T) {
+	emitErr := errors.New("emit failed")
+	var emitted, decoded int
+	decode := decodeArchives(100000, -1, nil)
+	countingDecode := func(handle func(*clinvar.VariationArchive) error) error {
+		return decode(func(variant *clinvar.VariationArchive) error {
+			decoded++
+			return handle(variant)
+		})
+	}
+	err := runPipeline(t, countingDecode, 4, func(variant clinvar.ClinVarVariationData) error {
+		emitted++
+		if emitted == 10 {
+			return emitErr
+		}
+		return nil
+	})
+	if !errors.Is(err, emitErr) {
+		t.Fatalf("got error %v, want %v", err, emitErr)
+	}
+	if emitted != 10 {
+		t.Errorf("emit called %d times, want it to stop after the error at 10", emitted)
+	}
+	//Cancelling stops the decoder long before the end of the input
+	if decoded == 100000 {
+		t.Error("decoder kept going after emit failed")
+	}
+}
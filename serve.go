@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// releaseServer answers lookups against an uncompressed release, reading each VariationArchive from its
+// indexed offset on request rather than holding the release in memory
+type releaseServer struct {
+	file        *os.File
+	assembly    string
+	releaseInfo clinvar.ClinVarDataReleaseInfo
+	accessions  map[string]indexEntry
+	genes       map[string][]indexEntry
+}
+
+func newReleaseServer(file *os.File, assembly string, releaseInfo clinvar.ClinVarDataReleaseInfo, entries []indexEntry) *releaseServer {
+	server := &releaseServer{
+		file:        file,
+		assembly:    assembly,
+		releaseInfo: releaseInfo,
+		accessions:  make(map[string]indexEntry),
+		genes:       make(map[string][]indexEntry)}
+	for _, entry := range entries {
+		server.accessions[entry.Accession] = entry
+		if entry.GeneSymbols == "" {
+			continue
+		}
+		for _, symbol := range strings.Split(entry.GeneSymbols, ";") {
+			server.genes[symbol] = append(server.genes[symbol], entry)
+		}
+	}
+	return server
+}
+
+func (server *releaseServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /release", server.release)
+	mux.HandleFunc("GET /variants/{accession}", server.variant)
+	mux.HandleFunc("GET /genes/{symbol}", server.gene)
+	return mux
+}
+
+func (server *releaseServer) release(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, server.releaseInfo)
+}
+
+func (server *releaseServer) variant(w http.ResponseWriter, r *http.Request) {
+	entry, ok := server.accessions[r.PathValue("accession")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	variant, err := clinvar.ReadVariationArchiveAt(server.file, entry.Offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, clinvar.Extract(variant, server.assembly))
+}
+
+func (server *releaseServer) gene(w http.ResponseWriter, r *http.Request) {
+	entries, ok := server.genes[r.PathValue("symbol")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, entries)
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Println(err)
+	}
+}
+
+func runServe(args []string) error {
+	flags := newFlagSet("serve")
+	var in inputFlags
+	in.register(flags)
+	indexFile := flags.String("index", "", "Path of an index written by the index command (the release is scanned at startup when empty)")
+	addr := flags.String("addr", ":8080", "Address to listen on")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if in.inputXML == "" {
+		return usageErrorf(flags, "-i is required: variants are read from the file at their offsets")
+	}
+
+	variantFile, err := openUncompressedRelease(in.inputXML)
+	if err != nil {
+		return err
+	}
+	defer variantFile.Close()
+	reader, err := clinvar.NewReader(variantFile)
+	if err != nil {
+		return err
+	}
+
+	var entries []indexEntry
+	if *indexFile != "" {
+		entries, err = readIndex(*indexFile)
+	} else {
+		err = buildIndex(reader, in.assembly, func(entry indexEntry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	server := newReleaseServer(variantFile, in.assembly, reader.ReleaseInfo(), entries)
+	fmt.Fprintf(os.Stderr, "Serving %d variants on %s\n", len(entries), *addr)
+	return http.ListenAndServe(*addr, server.handler())
+}
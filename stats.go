@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// releaseStats summarizes the variants of a release, counting them by each of a few key fields.
+// Chromosomes counts the variants by chromosome on the chosen assembly
+type releaseStats struct {
+	ReleaseInfo        clinvar.ClinVarDataReleaseInfo
+	Variants           int
+	ClinicalAssertions int
+	Genes              int
+	RecordTypes        map[string]int
+	VariationTypes     map[string]int
+	Interpretations    map[string]int
+	ReviewStatuses     map[string]int
+	Chromosomes        map[string]int
+	assembly           string
+	genes              map[string]bool
+}
+
+func newReleaseStats(releaseInfo clinvar.ClinVarDataReleaseInfo, assembly string) *releaseStats {
+	return &releaseStats{
+		ReleaseInfo:     releaseInfo,
+		assembly:        assembly,
+		RecordTypes:     make(map[string]int),
+		VariationTypes:  make(map[string]int),
+		Interpretations: make(map[string]int),
+		ReviewStatuses:  make(map[string]int),
+		Chromosomes:     make(map[string]int),
+		genes:           make(map[string]bool)}
+}
+
+func (stats *releaseStats) add(variant clinvar.ClinVarVariationData) error {
+	stats.Variants++
+	stats.ClinicalAssertions += len(variant.ClinicalAssertions)
+	stats.RecordTypes[variant.RecordType]++
+	stats.VariationTypes[variant.Type]++
+	stats.Interpretations[variant.Interpretation]++
+	stats.ReviewStatuses[variant.ReviewStatus]++
+	for _, location := range variant.Locations {
+		if location.Assembly == stats.assembly {
+			stats.Chromosomes[location.Chr]++
+			break
+		}
+	}
+	for _, gene := range variant.Genes {
+		if !stats.genes[gene.Symbol] {
+			stats.genes[gene.Symbol] = true
+			stats.Genes++
+		}
+	}
+	return nil
+}
+
+func runStats(args []string) error {
+	flags := newFlagSet("stats")
+	var in inputFlags
+	var filters filterFlags
+	in.register(flags)
+	filters.register(flags)
+	outputFile := flags.String("o", "", "Path of file to write the JSON summary to (stdout when empty)")
+	workers := flags.Int("workers", 1, "Number of goroutines extracting variants in parallel")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if err := filters.check(flags); err != nil {
+		return err
+	}
+	opts := streamOptions{Workers: *workers, Assembly: in.assembly}
+	if err := filters.apply(&opts); err != nil {
+		return err
+	}
+
+	reader, variantFile, err := openRelease(in.inputXML)
+	if err != nil {
+		return err
+	}
+	defer variantFile.Close()
+
+	stats := newReleaseStats(reader.ReleaseInfo(), in.assembly)
+	if err := opts.extractVariants(reader, stats.add); err != nil {
+		return err
+	}
+
+	out, err := createOutputFile(*outputFile)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(stats)
+	if out != os.Stdout {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"io"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// forEachVariationArchive hands every VariationArchive the reader decodes to handle, stopping at the first error
+func forEachVariationArchive(reader *clinvar.Reader, handle func(*clinvar.VariationArchive) error) error {
+	for {
+		variant, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := handle(variant); err != nil {
+			return err
+		}
+	}
+}
+
+// streamOptions controls how a streamed release is turned into output
+type streamOptions struct {
+	//WithReleaseInfo wraps the variants together with the release info of the input file
+	WithReleaseInfo bool
+	//Workers is the number of goroutines extracting variants; results are still written in input order
+	Workers int
+	//Format selects the variantWriter used for output (json, ndjson, tsv, csv or vcf)
+	Format string
+	//Assembly selects the location that populates the legacy flat location fields
+	Assembly string
+	//MaxAlleleFrequency drops variants whose highest population allele frequency is above it
+	MaxAlleleFrequency float64
+	//ChangedSince, when set, keeps only variants whose aggregate classification changed after this date
+	ChangedSince string
+	//Genes, when set, keeps only variants with one of these genes. It is checked before extraction
+	Genes clinvar.GeneSet
+	//Regions, when set, keeps only variants whose location on Assembly overlaps one of them. It is checked before extraction
+	Regions *clinvar.RegionSet
+}
+
+// selective reports whether any filter is checked on the decoded VariationArchive, before extraction
+func (opts streamOptions) selective() bool {
+	return opts.Genes != nil || opts.Regions != nil
+}
+
+// selects reports whether a decoded VariationArchive passes the filters that are checked before extraction
+func (opts streamOptions) selects(variant *clinvar.VariationArchive) bool {
+	if opts.Genes != nil && !variant.HasGene(opts.Genes) {
+		return false
+	}
+	return opts.Regions == nil || variant.InRegions(opts.Regions, opts.Assembly)
+}
+
+// forEachSelected hands every VariationArchive the reader decodes that passes the pre-extraction filters
+// to handle, so the others are skipped without being extracted
+func (opts streamOptions) forEachSelected(reader *clinvar.Reader, handle func(*clinvar.VariationArchive) error) error {
+	return forEachVariationArchive(reader, func(variant *clinvar.VariationArchive) error {
+		if !opts.selects(variant) {
+			return nil
+		}
+		return handle(variant)
+	})
+}
+
+// keep reports whether an extracted variant passes the output filters
+func (opts streamOptions) keep(variantInfo clinvar.ClinVarVariationData) bool {
+	if variantInfo.MaxAlleleFrequency > opts.MaxAlleleFrequency {
+		return false
+	}
+	if opts.ChangedSince != "" && !variantInfo.ClassificationChangedSince(opts.ChangedSince) {
+		return false
+	}
+	return true
+}
+
+// filter hands the variants that pass the output filters on to emit
+func (opts streamOptions) filter(emit func(clinvar.ClinVarVariationData) error) func(clinvar.ClinVarVariationData) error {
+	return func(variantInfo clinvar.ClinVarVariationData) error {
+		if !opts.keep(variantInfo) {
+			return nil
+		}
+		return emit(variantInfo)
+	}
+}
+
+// extract turns a decoded VariationArchive into the ClinVarVariationData that is written out
+func (opts streamOptions) extract(variant *clinvar.VariationArchive) clinvar.ClinVarVariationData {
+	return clinvar.Extract(variant, opts.Assembly)
+}
+
+// extractVariants extracts every variant the reader decodes, on opts.Workers goroutines, and hands
+// the ones that pass the output filters to emit in input order
+func (opts streamOptions) extractVariants(reader *clinvar.Reader, emit func(clinvar.ClinVarVariationData) error) error {
+	emit = opts.filter(emit)
+	if opts.Workers > 1 {
+		return extractVariantsConcurrently(func(handle func(*clinvar.VariationArchive) error) error {
+			return opts.forEachSelected(reader, handle)
+		}, opts.Workers, opts.extract, emit)
+	}
+	return opts.forEachSelected(reader, func(variant *clinvar.VariationArchive) error {
+		return emit(opts.extract(variant))
+	})
+}
+
+// streamXMLFileToOutput extracts each variant of the input file as it is decoded and writes it straight to the output
+func streamXMLFileToOutput(inputFile string, outputFile string, opts streamOptions) error {
+	reader, variantFile, err := openRelease(inputFile)
+	if err != nil {
+		return err
+	}
+	defer variantFile.Close()
+
+	writer, err := openVariantWriter(opts.Format, outputFile)
+	if err != nil {
+		return err
+	}
+	if opts.WithReleaseInfo {
+		err = writer.WriteReleaseInfo(reader.ReleaseInfo())
+	}
+	if err == nil {
+		err = opts.extractVariants(reader, writer.Write)
+	}
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
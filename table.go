@@ -0,0 +1,450 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// variantTable describes one relational table flattened out of ClinVarVariationData.
+// Child tables carry the parent VCV accession in their first column as a foreign key
+type variantTable struct {
+	name   string
+	header []string
+	rows   func(variant clinvar.ClinVarVariationData) [][]string
+}
+
+var variantTables = []variantTable{
+	{
+		name: "variants",
+		header: []string{"Accession", "Version", "Type", "GeneAffected", "GeneEntrezID", "GeneOmimID", "NcbiRefSeq",
+			"LocationType", "DbSNPID", "GenomeVersion", "ChromLocation", "ChromStart", "ChromStop", "Length", "OmimID", "ReviewStatus",
+			"Interpretation", "DateLastEvaluated", "MANESelectCoding", "MANESelectProtein", "MaxAlleleFrequency", "GMAF", "GMAFMinorAllele", "GMAFSource",
+			"GermlineObservations", "SomaticObservations", "DeNovoObservations", "NumberTested", "RecordType",
+			"Oncogenicity", "OncogenicityReviewStatus", "OncogenicityDateLastEvaluated"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			gmaf := struct {
+				clinvar.GlobalMinorAlleleFrequency
+				value string
+			}{}
+			if variant.GlobalMinorAlleleFrequency != nil {
+				gmaf.GlobalMinorAlleleFrequency = *variant.GlobalMinorAlleleFrequency
+				gmaf.value = tableFloat(gmaf.Value)
+			}
+			origins := variant.ObservationSummary.OriginCounts
+			oncogenicity := clinvar.Oncogenicity{}
+			if variant.Oncogenicity != nil {
+				oncogenicity = *variant.Oncogenicity
+			}
+			return [][]string{{variant.Accesssion, variant.Version, variant.Type, variant.GeneAffected, variant.GeneEntrezID,
+				variant.GeneOmimID, variant.NcbiRefSeq, variant.LocationType, variant.DbSNPID, variant.GenomeVersion,
+				variant.ChromLocation, variant.ChromStart, variant.ChromStop, variant.Length, variant.OmimID, variant.ReviewStatus,
+				variant.Interpretation, variant.DateLastEvaluated, variant.MANESelectCoding, variant.MANESelectProtein, tableFloat(variant.MaxAlleleFrequency),
+				gmaf.value, gmaf.MinorAllele, gmaf.Source, strconv.Itoa(origins["germline"]), strconv.Itoa(origins["somatic"]),
+				strconv.Itoa(origins["de novo"]), strconv.Itoa(variant.ObservationSummary.NumberTested), variant.RecordType,
+				oncogenicity.Description, oncogenicity.ReviewStatus, oncogenicity.DateLastEvaluated}}
+		},
+	},
+	{
+		name: "locations",
+		header: []string{"VariantAccession", "Assembly", "Chr", "Accession", "Start", "Stop", "DisplayStart", "DisplayStop",
+			"Length", "PositionVCF", "ReferenceAlleleVCF", "AlternateAlleleVCF"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, location := range variant.Locations {
+				rows = append(rows, []string{variant.Accesssion, location.Assembly, location.Chr, location.Accession,
+					tableInt(location.Start), tableInt(location.Stop), tableInt(location.DisplayStart), tableInt(location.DisplayStop),
+					tableInt(location.Length), tableInt(location.PositionVCF), location.ReferenceAlleleVCF, location.AlternateAlleleVCF})
+			}
+			return rows
+		},
+	},
+	{
+		name: "genes",
+		header: []string{"VariantAccession", "Symbol", "FullName", "GeneID", "HGNC_ID", "OMIM", "RelationshipType", "Source",
+			"CytogeneticLocation", "Haploinsufficiency", "HaploinsufficiencyLastEvaluated", "Triplosensitivity", "TriplosensitivityLastEvaluated"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, gene := range variant.Genes {
+				rows = append(rows, []string{variant.Accesssion, gene.Symbol, gene.FullName, gene.GeneID, gene.HGNCID, gene.OMIM,
+					gene.RelationshipType, gene.Source, gene.CytogeneticLocation, gene.Haploinsufficiency.Score,
+					gene.Haploinsufficiency.LastEvaluated, gene.Triplosensitivity.Score, gene.Triplosensitivity.LastEvaluated})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "gene_locations",
+		header: []string{"VariantAccession", "GeneID", "Assembly", "Chr", "Accession", "Start", "Stop", "Strand", "LocationType", "DistanceToGene"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, gene := range variant.Genes {
+				for _, geneLocation := range gene.Locations {
+					rows = append(rows, []string{variant.Accesssion, gene.GeneID, geneLocation.Assembly, geneLocation.Chr,
+						geneLocation.Accession, tableInt(geneLocation.Start), tableInt(geneLocation.Stop), geneLocation.Strand,
+						geneLocation.LocationType, strconv.Itoa(geneLocation.DistanceToGene)})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name: "member_alleles",
+		header: []string{"VariantAccession", "ParentVariationID", "HaplotypeVariationID", "AlleleID", "VariationID", "Name",
+			"VariantType", "CanonicalSPDI", "DbSNPID", "GeneSymbols"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, member := range variant.MemberAlleles {
+				rows = append(rows, []string{variant.Accesssion, member.ParentVariationID, member.HaplotypeVariationID, member.AlleleID,
+					member.VariationID, member.Name, member.VariantType, member.CanonicalSPDI, member.DbSNPID,
+					strings.Join(member.GeneSymbols, ",")})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "included_in",
+		header: []string{"VariantAccession", "IncludedInAccession"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, accession := range variant.IncludedIn {
+				rows = append(rows, []string{variant.Accesssion, accession})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "somatic_clinical_impacts",
+		header: []string{"VariantAccession", "Tier", "AssertionType", "ClinicalSignificance", "ReviewStatus", "DateLastEvaluated"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			if variant.SomaticClinicalImpact == nil {
+				return nil
+			}
+			var rows [][]string
+			for _, tier := range variant.SomaticClinicalImpact.Tiers {
+				rows = append(rows, []string{variant.Accesssion, tier.Tier, tier.AssertionType, tier.ClinicalSignificance,
+					variant.SomaticClinicalImpact.ReviewStatus, variant.SomaticClinicalImpact.DateLastEvaluated})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "rcvs",
+		header: []string{"VariantAccession", "AccessionID", "Version", "Interpretation", "Condition", "SubmissionCount", "ReviewStatus", "MedGenID", "TraitSetID"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, rcv := range variant.RCVData {
+				rows = append(rows, []string{variant.Accesssion, rcv.AccessionID, rcv.Version, rcv.Interpretation, rcv.Condition,
+					rcv.SubmissionCount, rcv.ReviewStatus, rcv.MedGenID, rcv.TraitSetID})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "consequences",
+		header: []string{"VariantAccession", "Consequence"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, hgv := range variant.HGVData {
+				rows = append(rows, []string{variant.Accesssion, hgv.Consequence})
+			}
+			return rows
+		},
+	},
+	{
+		name: "hgvs",
+		header: []string{"VariantAccession", "Type", "Assembly", "NucleotideExpression", "SequenceAccessionVersion", "Change",
+			"ProteinExpression", "ProteinAccessionVersion", "ProteinChange", "MANESelect", "MolecularConsequence", "MolecularConsequenceID"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, hgvs := range variant.HGVSExpressions {
+				consequences := hgvs.MolecularConsequences
+				if len(consequences) == 0 {
+					consequences = []clinvar.MolecularConsequence{{}}
+				}
+				//One row per consequence so the table stays flat
+				for _, consequence := range consequences {
+					rows = append(rows, []string{variant.Accesssion, hgvs.Type, hgvs.Assembly, hgvs.NucleotideExpression,
+						hgvs.SequenceAccessionVersion, hgvs.Change, hgvs.ProteinExpression, hgvs.ProteinAccessionVersion,
+						hgvs.ProteinChange, strconv.FormatBool(hgvs.MANESelect), consequence.Type, consequence.ID})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name:   "classification_history",
+		header: []string{"VariantAccession", "Date", "Description"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, history := range variant.ClassificationHistory {
+				rows = append(rows, []string{variant.Accesssion, history.Date, history.Description})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "xrefs",
+		header: []string{"VariantAccession", "DB", "ID", "Type"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, db := range sortedXRefDBs(variant.XRefs) {
+				for _, xref := range variant.XRefs[db] {
+					rows = append(rows, []string{variant.Accesssion, db, xref.ID, xref.Type})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name:   "allele_frequencies",
+		header: []string{"VariantAccession", "Source", "SourceKey", "Value"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, frequency := range variant.AlleleFrequencies {
+				rows = append(rows, []string{variant.Accesssion, frequency.Source, frequency.SourceKey, tableFloat(frequency.Value)})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "citations",
+		header: []string{"VariantAccession", "CitationSource", "CitationID", "URL"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, citation := range variant.ClinicalInterpretations.Citations {
+				rows = append(rows, []string{variant.Accesssion, citation.CitationSource, citation.CitationID, citation.URL})
+			}
+			return rows
+		},
+	},
+	{
+		name: "traits",
+		header: []string{"VariantAccession", "ID", "Name", "PhenotypicSeries", "MIM", "MedGen", "Orph", "AlternateNames", "Symbol",
+			"AlternateSymbols", "ModeOfInheritance", "AgeOfOnset", "Prevalence", "DiseaseMechanism", "PublicDefinition",
+			"GeneReviewsShort", "GeneReviews"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, trait := range variant.ClinicalInterpretations.Trait {
+				rows = append(rows, []string{variant.Accesssion, trait.ID, trait.Name, trait.PhenotypicSeries, trait.MIM, trait.MedGen, trait.Orph,
+					strings.Join(trait.AlternateNames, ";"), trait.Symbol, strings.Join(trait.AlternateSymbols, ";"),
+					strings.Join(trait.ModeOfInheritance, ";"), strings.Join(trait.AgeOfOnset, ";"), strings.Join(trait.Prevalence, ";"),
+					strings.Join(trait.DiseaseMechanism, ";"), trait.PublicDefinition, trait.GeneReviewsShort, strings.Join(trait.GeneReviews, ";")})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "trait_attributes",
+		header: []string{"VariantAccession", "TraitID", "Type", "Value", "IntegerValue"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, trait := range variant.ClinicalInterpretations.Trait {
+				for _, attribute := range trait.Attributes {
+					rows = append(rows, []string{variant.Accesssion, trait.ID, attribute.Type, attribute.Value, tableInt(attribute.IntegerValue)})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name:   "trait_attribute_xrefs",
+		header: []string{"VariantAccession", "TraitID", "AttributeType", "DB", "ID", "Type"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, trait := range variant.ClinicalInterpretations.Trait {
+				for _, attribute := range trait.Attributes {
+					for _, db := range sortedXRefDBs(attribute.XRefs) {
+						for _, xref := range attribute.XRefs[db] {
+							rows = append(rows, []string{variant.Accesssion, trait.ID, attribute.Type, db, xref.ID, xref.Type})
+						}
+					}
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name:   "trait_citations",
+		header: []string{"VariantAccession", "TraitID", "CitationSource", "CitationID"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, trait := range variant.ClinicalInterpretations.Trait {
+				for _, citation := range trait.Citations {
+					rows = append(rows, []string{variant.Accesssion, trait.ID, citation.CitationSource, citation.CitationID})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name: "clinical_assertions",
+		header: []string{"VariantAccession", "ID", "Accession", "Version", "SubmitterName", "OrgID", "OrganizationCategory",
+			"OrgAbbreviation", "SubmissionDate", "DateLastUpdated", "RecordStatus", "ReviewStatus", "Interpretation",
+			"DateLastEvaluated", "AssertionMethod", "ModeOfInheritance", "Comments", "Oncogenicity", "SomaticClinicalImpactTier",
+			"SomaticClinicalImpactAssertionType", "SomaticClinicalImpactClinicalSignificance"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, assertion := range variant.ClinicalAssertions {
+				somatic := clinvar.SomaticClinicalImpactTier{}
+				if assertion.SomaticClinicalImpact != nil {
+					somatic = *assertion.SomaticClinicalImpact
+				}
+				rows = append(rows, []string{variant.Accesssion, assertion.ID, assertion.Accession, assertion.Version,
+					assertion.SubmitterName, assertion.OrgID, assertion.OrganizationCategory, assertion.OrgAbbreviation,
+					assertion.SubmissionDate, assertion.DateLastUpdated, assertion.RecordStatus, assertion.ReviewStatus,
+					assertion.Interpretation, assertion.DateLastEvaluated, assertion.AssertionMethod, assertion.ModeOfInheritance,
+					strings.Join(assertion.Comments, "\n"), assertion.Oncogenicity, somatic.Tier, somatic.AssertionType,
+					somatic.ClinicalSignificance})
+			}
+			return rows
+		},
+	},
+	{
+		name:   "clinical_assertion_conditions",
+		header: []string{"VariantAccession", "ClinicalAssertionAccession", "TraitType", "SubmittedName", "SubmittedXRef", "MedGenCUI", "MedGenName"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, assertion := range variant.ClinicalAssertions {
+				for _, condition := range assertion.Conditions {
+					rows = append(rows, []string{variant.Accesssion, assertion.Accession, condition.TraitType, condition.SubmittedName,
+						condition.SubmittedXRef, condition.MedGenCUI, condition.MedGenName})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name: "observations",
+		header: []string{"VariantAccession", "ClinicalAssertionAccession", "Origin", "Species", "TaxonomyID", "AffectedStatus",
+			"NumberTested", "MethodType", "TypePlatform"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, assertion := range variant.ClinicalAssertions {
+				for _, observation := range assertion.Observations {
+					rows = append(rows, []string{variant.Accesssion, assertion.Accession, observation.Origin, observation.Species,
+						observation.TaxonomyID, observation.AffectedStatus, strconv.Itoa(observation.NumberTested),
+						observation.MethodType, observation.TypePlatform})
+				}
+			}
+			return rows
+		},
+	},
+	{
+		name:   "clinical_assertion_citations",
+		header: []string{"VariantAccession", "ClinicalAssertionAccession", "CitationSource", "CitationID", "URL"},
+		rows: func(variant clinvar.ClinVarVariationData) [][]string {
+			var rows [][]string
+			for _, assertion := range variant.ClinicalAssertions {
+				for _, citation := range assertion.Citations {
+					rows = append(rows, []string{variant.Accesssion, assertion.Accession, citation.CitationSource, citation.CitationID, citation.URL})
+				}
+			}
+			return rows
+		},
+	},
+}
+
+// sortedXRefDBs lists the DBs of an XRef map in order; map iteration order is random,
+// so sorting keeps the output reproducible
+func sortedXRefDBs(xrefs map[string][]clinvar.XRef) []string {
+	var dbs []string
+	for db := range xrefs {
+		dbs = append(dbs, db)
+	}
+	sort.Strings(dbs)
+	return dbs
+}
+
+// tableInt renders a coordinate, leaving the field empty (NULL on COPY) when it was not provided
+func tableInt(coordinate int) string {
+	if coordinate == 0 {
+		return ""
+	}
+	return strconv.Itoa(coordinate)
+}
+
+func tableFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// tableWriter flattens variants into one delimited file per variantTable, each with a header row.
+// Fields are quoted CSV-style when needed, so the files load with
+// COPY ... WITH (FORMAT csv, HEADER, DELIMITER E'\t') for the tsv flavour
+type tableWriter struct {
+	dir       string
+	comma     rune
+	extension string
+	files     []*os.File
+	writers   []*csv.Writer
+}
+
+func newTableWriter(dir string, comma rune, extension string) (*tableWriter, error) {
+	if len(dir) == 0 {
+		return nil, errors.New("tabular output needs an output directory (-o)")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	w := &tableWriter{dir: dir, comma: comma, extension: extension}
+	for _, table := range variantTables {
+		if err := w.createTable(table.name, table.header); err != nil {
+			w.Close()
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *tableWriter) createTable(name string, header []string) error {
+	file, err := os.Create(filepath.Join(w.dir, name+w.extension))
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	writer.Comma = w.comma
+	w.files = append(w.files, file)
+	w.writers = append(w.writers, writer)
+	return writer.Write(header)
+}
+
+// WriteReleaseInfo adds a single-row release table next to the variant tables
+func (w *tableWriter) WriteReleaseInfo(releaseInfo clinvar.ClinVarDataReleaseInfo) error {
+	if err := w.createTable("release", []string{"W3SchemaInfo", "ClinVarSchemaVersion", "SchemaVersion", "ClinVarReleaseDate"}); err != nil {
+		return err
+	}
+	return w.writers[len(w.writers)-1].Write([]string{releaseInfo.W3SchemaInfo, releaseInfo.ClinVarSchemaVersion, releaseInfo.SchemaVersion, releaseInfo.ClinVarReleaseDate})
+}
+
+func (w *tableWriter) Write(variant clinvar.ClinVarVariationData) error {
+	for i, table := range variantTables {
+		//csv.Writer buffers rows; they are flushed once, on Close, rather than per variant
+		for _, row := range table.rows(variant) {
+			if err := w.writers[i].Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *tableWriter) Close() error {
+	var firstErr error
+	for i, writer := range w.writers {
+		writer.Flush()
+		if err := writer.Error(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := w.files[i].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// recordTypes are the VariationArchive RecordType values the extraction understands
+var recordTypes = map[string]bool{"interpreted": true, "included": true, "classified": true}
+
+// validator collects the problems found in the variants of a release
+type validator struct {
+	assembly   string
+	variants   int
+	problems   int
+	accessions map[string]bool
+}
+
+func (v *validator) report(accession string, format string, args ...interface{}) {
+	v.problems++
+	fmt.Printf("%s\t%s\n", accession, fmt.Sprintf(format, args...))
+}
+
+// check reports whatever is inconsistent about one VariationArchive once it has been extracted
+func (v *validator) check(variant *clinvar.VariationArchive) error {
+	v.variants++
+	accession := variant.Accession
+	if accession == "" {
+		accession = fmt.Sprintf("#%d", v.variants)
+		v.report(accession, "missing Accession")
+	} else if v.accessions[accession] {
+		v.report(accession, "duplicate Accession")
+	}
+	v.accessions[accession] = true
+	if variant.VariationID == "" {
+		v.report(accession, "missing VariationID")
+	}
+	if !recordTypes[variant.RecordType] {
+		v.report(accession, "unknown RecordType %q", variant.RecordType)
+	}
+
+	variantInfo := clinvar.Extract(variant, v.assembly)
+	//Older records often place the variant only on a cytogenetic band, which counts as a location
+	hasCytogeneticLocation := variantInfo.ChromLocation != "" && variantInfo.ChromLocation != clinvar.NotProvided
+	if len(variantInfo.Locations) == 0 && len(variantInfo.MemberAlleles) == 0 && !hasCytogeneticLocation {
+		v.report(accession, "no location and no member alleles")
+	}
+	for _, location := range variantInfo.Locations {
+		if location.Start > location.Stop {
+			v.report(accession, "%s location starts at %d after it stops at %d", location.Assembly, location.Start, location.Stop)
+		}
+	}
+	if variant.RecordType != "included" && !hasClassification(variantInfo) {
+		v.report(accession, "%s record without a classification", variant.RecordType)
+	}
+	return nil
+}
+
+// hasClassification reports whether a variant has a germline, somatic clinical impact or oncogenicity
+// classification. Schema 2.x records can carry only the latter two
+func hasClassification(variantInfo clinvar.ClinVarVariationData) bool {
+	germline := variantInfo.Interpretation != "" && variantInfo.Interpretation != clinvar.NotProvided
+	return germline || variantInfo.SomaticClinicalImpact != nil || variantInfo.Oncogenicity != nil
+}
+
+func runValidate(args []string) error {
+	flags := newFlagSet("validate")
+	var in inputFlags
+	in.register(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	reader, variantFile, err := openRelease(in.inputXML)
+	if err != nil {
+		return err
+	}
+	defer variantFile.Close()
+
+	v := validator{assembly: in.assembly, accessions: make(map[string]bool)}
+	if err := forEachVariationArchive(reader, v.check); err != nil {
+		return fmt.Errorf("after %d variants: %w", v.variants, err)
+	}
+	if v.problems > 0 {
+		return fmt.Errorf("%d problems found in %d variants", v.problems, v.variants)
+	}
+	fmt.Fprintf(os.Stderr, "%d variants, no problems found\n", v.variants)
+	return nil
+}
@@ -0,0 +1,15 @@
+package main
+
+import "testing"
+
+func TestValidateShippedSamples(t *testing.T) {
+	for _, sample := range []string{
+		"ClinVarVariationRelease_head.xml",
+		"ClinVarVariationRelease_p53Example.xml",
+		"clinvar/testdata/ClinVarVariationRelease_2.0Example.xml",
+	} {
+		if err := runValidate([]string{"-i", sample}); err != nil {
+			t.Errorf("validate %s: %v", sample, err)
+		}
+	}
+}
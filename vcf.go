@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+// vcfAssemblies are the assemblies a VCF file is written for
+var vcfAssemblies = []string{"GRCh37", "GRCh38"}
+
+// vcfInfoHeader describes every INFO key written by vcfWriter
+var vcfInfoHeader = []string{
+	`##INFO=<ID=VCV,Number=1,Type=String,Description="ClinVar variation archive (VCV) accession">`,
+	`##INFO=<ID=GENE,Number=.,Type=String,Description="Symbols of the genes the variant affects">`,
+	`##INFO=<ID=CLNREVSTAT,Number=1,Type=String,Description="ClinVar review status of the aggregate interpretation">`,
+	`##INFO=<ID=RCV,Number=.,Type=String,Description="ClinVar RCV accessions, one per interpreted condition">`,
+	`##INFO=<ID=CLNSIG,Number=.,Type=String,Description="Interpretation of each RCV, in the same order as RCV">`,
+	`##INFO=<ID=MC,Number=.,Type=String,Description="Molecular consequences of the variant">`,
+	`##INFO=<ID=ONC,Number=1,Type=String,Description="Aggregate oncogenicity classification">`,
+	`##INFO=<ID=SCI,Number=.,Type=String,Description="Aggregate somatic clinical impact tiers">`,
+}
+
+// vcfInfoEscaper follows the ClinVar VCF convention of underscores for spaces and
+// percent-encodes the characters that would break INFO parsing
+var vcfInfoEscaper = strings.NewReplacer(" ", "_", "%", "%25", ";", "%3B", "=", "%3D", ",", "%2C", "\t", "%09", "\n", "%0A")
+
+// vcfRecord is a data line of one chromosome, read back from its spill file to be sorted by position
+type vcfRecord struct {
+	pos  int
+	line string
+}
+
+// vcfSpill is the temporary file the data lines of one assembly and chromosome are appended to until Close
+type vcfSpill struct {
+	file *os.File
+	out  *bufio.Writer
+}
+
+// vcfWriter writes one VCFv4.2 file per assembly from the VCF-normalized alleles of each variant location.
+// VCF requires records sorted by position, so data lines are spilled to a temporary file per chromosome
+// and each chromosome is sorted on Close; only the largest chromosome is ever held in memory
+type vcfWriter struct {
+	dir         string
+	spillDir    string
+	releaseInfo *clinvar.ClinVarDataReleaseInfo
+	spills      map[string]map[string]*vcfSpill
+}
+
+func newVCFWriter(dir string) (*vcfWriter, error) {
+	if len(dir) == 0 {
+		return nil, errors.New("vcf output needs an output directory (-o)")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	//Spill files go next to the output, which has to have room for the same data anyway
+	spillDir, err := os.MkdirTemp(dir, ".vcf-spill-")
+	if err != nil {
+		return nil, err
+	}
+	return &vcfWriter{dir: dir, spillDir: spillDir, spills: make(map[string]map[string]*vcfSpill)}, nil
+}
+
+// WriteReleaseInfo stamps the VCF headers with the release date and schema of the input
+func (w *vcfWriter) WriteReleaseInfo(releaseInfo clinvar.ClinVarDataReleaseInfo) error {
+	w.releaseInfo = &releaseInfo
+	return nil
+}
+
+// Write adds a record per assembly for the variant, and for Haplotype and Genotype records one per
+// member allele, carrying the parent VCV's INFO fields with the member's own genes
+func (w *vcfWriter) Write(variant clinvar.ClinVarVariationData) error {
+	var symbols []string
+	for _, gene := range variant.Genes {
+		symbols = append(symbols, gene.Symbol)
+	}
+	if err := w.addRecords(variant.Locations, vcfID(variant.DbSNPID), vcfInfo(variant, symbols)); err != nil {
+		return err
+	}
+	for _, member := range variant.MemberAlleles {
+		if err := w.addRecords(member.Locations, vcfID(member.DbSNPID), vcfInfo(variant, member.GeneSymbols)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *vcfWriter) addRecords(locations []clinvar.VariantLocation, id string, info string) error {
+	for _, allele := range locations {
+		if !isVCFAssembly(allele.Assembly) || allele.PositionVCF == 0 || allele.ReferenceAlleleVCF == "" || allele.AlternateAlleleVCF == "" {
+			continue
+		}
+		spill, err := w.spill(allele.Assembly, allele.Chr)
+		if err != nil {
+			return err
+		}
+		line := strings.Join([]string{allele.Chr, strconv.Itoa(allele.PositionVCF), id, allele.ReferenceAlleleVCF,
+			allele.AlternateAlleleVCF, ".", ".", info}, "\t")
+		if _, err := spill.out.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// spill returns the spill file of a chromosome, creating it for the chromosome's first record
+func (w *vcfWriter) spill(assembly string, chr string) (*vcfSpill, error) {
+	if spill, ok := w.spills[assembly][chr]; ok {
+		return spill, nil
+	}
+	file, err := os.CreateTemp(w.spillDir, assembly+"-")
+	if err != nil {
+		return nil, err
+	}
+	if w.spills[assembly] == nil {
+		w.spills[assembly] = make(map[string]*vcfSpill)
+	}
+	spill := &vcfSpill{file: file, out: bufio.NewWriter(file)}
+	w.spills[assembly][chr] = spill
+	return spill, nil
+}
+
+func (w *vcfWriter) Close() error {
+	var err error
+	for _, assembly := range vcfAssemblies {
+		if err = w.writeAssembly(assembly); err != nil {
+			break
+		}
+	}
+	for _, spills := range w.spills {
+		for _, spill := range spills {
+			spill.file.Close()
+		}
+	}
+	if removeErr := os.RemoveAll(w.spillDir); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+func (w *vcfWriter) writeAssembly(assembly string) error {
+	spills := w.spills[assembly]
+	var contigs []string
+	for chr := range spills {
+		contigs = append(contigs, chr)
+	}
+	sort.Slice(contigs, func(i, j int) bool {
+		return chromosomeLess(contigs[i], contigs[j])
+	})
+
+	file, err := os.Create(filepath.Join(w.dir, "clinvar_"+assembly+".vcf"))
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(file)
+
+	fmt.Fprintln(out, "##fileformat=VCFv4.2")
+	if w.releaseInfo != nil {
+		fmt.Fprintf(out, "##fileDate=%s\n", strings.ReplaceAll(w.releaseInfo.ClinVarReleaseDate, "-", ""))
+		fmt.Fprintf(out, "##clinvar_schema=%s\n", w.releaseInfo.ClinVarSchemaVersion)
+	}
+	fmt.Fprintln(out, "##source=clinVarXMLParser")
+	fmt.Fprintf(out, "##reference=%s\n", assembly)
+	for _, contig := range contigs {
+		fmt.Fprintf(out, "##contig=<ID=%s,assembly=%s>\n", contig, assembly)
+	}
+	for _, info := range vcfInfoHeader {
+		fmt.Fprintln(out, info)
+	}
+	fmt.Fprintln(out, "#CHROM\tPOS\tID\tREF\tALT\tQUAL\tFILTER\tINFO")
+	for _, contig := range contigs {
+		if err = writeSortedSpill(out, spills[contig]); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = out.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeSortedSpill reads back the data lines of one chromosome and writes them sorted by position
+func writeSortedSpill(out *bufio.Writer, spill *vcfSpill) error {
+	if err := spill.out.Flush(); err != nil {
+		return err
+	}
+	if _, err := spill.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	records := []vcfRecord{}
+	lines := bufio.NewReader(spill.file)
+	for {
+		line, err := lines.ReadString('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		fields := strings.SplitN(line, "\t", 3)
+		pos, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return fmt.Errorf("corrupt VCF spill file %s: %w", spill.file.Name(), err)
+		}
+		records = append(records, vcfRecord{pos: pos, line: line})
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].pos < records[j].pos
+	})
+	for _, record := range records {
+		if _, err := out.WriteString(record.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isVCFAssembly(assembly string) bool {
+	for _, vcfAssembly := range vcfAssemblies {
+		if assembly == vcfAssembly {
+			return true
+		}
+	}
+	return false
+}
+
+// chromosomeRank orders chromosomes 1-22, X, Y, MT, then anything else
+func chromosomeRank(chr string) int {
+	if n, err := strconv.Atoi(chr); err == nil {
+		return n
+	}
+	switch chr {
+	case "X":
+		return 23
+	case "Y":
+		return 24
+	case "MT":
+		return 25
+	}
+	return 26
+}
+
+// chromosomeLess orders chromosomes by chromosomeRank and contigs of the same rank by name,
+// so that every contig's records stay together
+func chromosomeLess(a, b string) bool {
+	if rankA, rankB := chromosomeRank(a), chromosomeRank(b); rankA != rankB {
+		return rankA < rankB
+	}
+	return a < b
+}
+
+// vcfID returns the dbSNP rs ID, or "." when there is none
+func vcfID(dbSNPID string) string {
+	if dbSNPID == "" || dbSNPID == clinvar.NotProvided {
+		return "."
+	}
+	return "rs" + dbSNPID
+}
+
+// vcfInfo builds the INFO field of a variant's records, with GENE listing geneSymbols
+func vcfInfo(variant clinvar.ClinVarVariationData, geneSymbols []string) string {
+	fields := []string{"VCV=" + vcfInfoEscaper.Replace(variant.Accesssion)}
+	fields = appendVCFInfoList(fields, "GENE", geneSymbols)
+	if variant.ReviewStatus != "" {
+		fields = append(fields, "CLNREVSTAT="+vcfInfoEscaper.Replace(variant.ReviewStatus))
+	}
+	var rcvs, interpretations []string
+	for _, rcv := range variant.RCVData {
+		rcvs = append(rcvs, rcv.AccessionID)
+		interpretations = append(interpretations, rcv.Interpretation)
+	}
+	fields = appendVCFInfoList(fields, "RCV", rcvs)
+	fields = appendVCFInfoList(fields, "CLNSIG", interpretations)
+	var consequences []string
+	for _, hgv := range variant.HGVData {
+		consequences = append(consequences, hgv.Consequence)
+	}
+	fields = appendVCFInfoList(fields, "MC", consequences)
+	if variant.Oncogenicity != nil && variant.Oncogenicity.Description != "" {
+		fields = append(fields, "ONC="+vcfInfoEscaper.Replace(variant.Oncogenicity.Description))
+	}
+	if variant.SomaticClinicalImpact != nil {
+		var tiers []string
+		for _, tier := range variant.SomaticClinicalImpact.Tiers {
+			tiers = append(tiers, tier.Tier)
+		}
+		fields = appendVCFInfoList(fields, "SCI", tiers)
+	}
+	return strings.Join(fields, ";")
+}
+
+// appendVCFInfoList adds a list INFO field, writing "." for each missing value so that lists such as
+// RCV and CLNSIG stay aligned. The field is left out when every value is missing
+func appendVCFInfoList(fields []string, key string, values []string) []string {
+	escaped := make([]string, len(values))
+	missing := 0
+	for i, value := range values {
+		if value == "" {
+			escaped[i] = "."
+			missing++
+			continue
+		}
+		escaped[i] = vcfInfoEscaper.Replace(value)
+	}
+	if missing == len(values) {
+		return fields
+	}
+	return append(fields, key+"="+strings.Join(escaped, ","))
+}
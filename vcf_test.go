@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/SowmithDaram/clinvar-xml-parser/clinvar"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files under testdata")
+
+// vcfLocation is a location with VCF-normalized alleles
+func vcfLocation(assembly string, chr string, pos int, ref string, alt string) clinvar.VariantLocation {
+	return clinvar.VariantLocation{Assembly: assembly, Chr: chr, Start: pos, Stop: pos + len(ref) - 1,
+		PositionVCF: pos, ReferenceAlleleVCF: ref, AlternateAlleleVCF: alt}
+}
+
+// vcfTestVariants are written out of order, on standard and unplaced contigs, with values that need escaping
+// and with empty list elements
+var vcfTestVariants = []clinvar.ClinVarVariationData{
+	{
+		Accesssion:   "VCV000000010",
+		DbSNPID:      "11540652",
+		ReviewStatus: "criteria provided, multiple submitters, no conflicts",
+		Genes:        []clinvar.Gene{{Symbol: "TP53"}},
+		RCVData: []clinvar.RCVData{
+			{AccessionID: "RCV000000001", Interpretation: "Pathogenic; low penetrance"},
+			{AccessionID: "RCV000000002"}},
+		HGVData: []clinvar.HGVData{{Consequence: "missense variant"}, {Consequence: ""}},
+		Locations: []clinvar.VariantLocation{
+			vcfLocation("GRCh38", "17", 7674220, "C", "T"),
+			vcfLocation("GRCh37", "17", 7577538, "C", "T")},
+	},
+	{
+		Accesssion:   "VCV000000011",
+		DbSNPID:      clinvar.NotProvided,
+		ReviewStatus: "no assertion criteria provided",
+		RCVData:      []clinvar.RCVData{{AccessionID: "RCV000000003"}},
+		HGVData:      []clinvar.HGVData{{Consequence: ""}},
+		Oncogenicity: &clinvar.Oncogenicity{Description: "Likely oncogenic"},
+		SomaticClinicalImpact: &clinvar.SomaticClinicalImpact{Tiers: []clinvar.SomaticClinicalImpactTier{
+			{Tier: "Tier I - Strong"}, {Tier: ""}}},
+		Locations: []clinvar.VariantLocation{
+			vcfLocation("GRCh38", "KI270742.1", 500, "A", "G"),
+			vcfLocation("GRCh38", "GL000220.1", 900, "G", "A"),
+			vcfLocation("GRCh38", "10", 100, "T", "TA"),
+			vcfLocation("GRCh37", "MT", 73, "A", "G")},
+	},
+	{
+		Accesssion: "VCV000000012",
+		DbSNPID:    "1",
+		Genes:      []clinvar.Gene{{Symbol: "A=B,C%D"}},
+		Locations: []clinvar.VariantLocation{
+			vcfLocation("GRCh38", "KI270742.1", 200, "C", "G"),
+			vcfLocation("GRCh38", "2", 300, "G", "C"),
+			vcfLocation("GRCh38", "X", 10, "A", "C"),
+			vcfLocation("GRCh38", "17", 7674000, "CT", "C"),
+			//Skipped: no VCF alleles, and an assembly without a VCF file
+			{Assembly: "GRCh38", Chr: "1", Start: 5, Stop: 5},
+			vcfLocation("NCBI36", "17", 100, "A", "G")},
+	},
+	{
+		Accesssion:   "VCV000000013",
+		ReviewStatus: "criteria provided, single submitter",
+		RecordType:   "interpreted",
+		MemberAlleles: []clinvar.MemberAllele{
+			{DbSNPID: "2", GeneSymbols: []string{"CFTR"}, Locations: []clinvar.VariantLocation{vcfLocation("GRCh38", "7", 117559590, "ATCT", "A")}},
+			{DbSNPID: clinvar.NotProvided, GeneSymbols: []string{}, Locations: []clinvar.VariantLocation{vcfLocation("GRCh38", "7", 117540230, "G", "A")}}},
+	},
+}
+
+func TestVCFWriterGolden(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := newVCFWriter(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = writer.WriteReleaseInfo(clinvar.ClinVarDataReleaseInfo{
+		ClinVarSchemaVersion: "http://ftp.ncbi.nlm.nih.gov/pub/clinvar/xsd_public/ClinVar_VCV_2.0.xsd",
+		ClinVarReleaseDate:   "2024-02-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, variant := range vcfTestVariants {
+		if err := writer.Write(variant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	//The spill files are removed once the VCF files are written
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "clinvar_GRCh37.vcf" || names[1] != "clinvar_GRCh38.vcf" {
+		t.Errorf("output directory holds %v, want only the two VCF files", names)
+	}
+
+	for _, assembly := range vcfAssemblies {
+		name := "clinvar_" + assembly + ".vcf"
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		golden := filepath.Join("testdata", "vcf", name)
+		if *updateGolden {
+			if err := os.WriteFile(golden, got, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s differs from %s:\n%s", name, golden, got)
+		}
+	}
+}
+
+func TestChromosomeLess(t *testing.T) {
+	chrs := []string{"KI270742.1", "MT", "10", "X", "GL000220.1", "2", "Y", "1"}
+	sort.Slice(chrs, func(i, j int) bool {
+		return chromosomeLess(chrs[i], chrs[j])
+	})
+	want := []string{"1", "2", "10", "X", "Y", "MT", "GL000220.1", "KI270742.1"}
+	for i := range want {
+		if chrs[i] != want[i] {
+			t.Fatalf("sorted chromosomes = %v, want %v", chrs, want)
+		}
+	}
+}